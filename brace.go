@@ -0,0 +1,536 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExpandBraces performs bash-style brace expansion on the provided pattern.
+//
+// A brace expression is either a comma-separated list of alternatives, such
+// as "{us,eu}", or a sequence expression of the form "{x..y}" or
+// "{x..y..step}", where x and y are both integers or both single letters.
+// Sequences whose endpoints begin with a zero are zero-padded to the same
+// width. Brace expressions may be nested, and a backslash escapes the
+// character that follows it. Braces that do not form a valid expression are
+// left as they are, just as bash does.
+//
+// The expansions are emitted left to right, so "{a,b}{1,2}" expands to "a1",
+// "a2", "b1", "b2".
+//
+// The result is not bounded, so untrusted patterns should be expanded with
+// ExpandBracesLimited.
+func ExpandBraces(pattern string) []string {
+	return parseBraces(pattern).expand()
+}
+
+// ExpandBracesLimited is ExpandBraces, but returns a *TooManyResultsError
+// instead if the result would exceed the provided Limits.
+func ExpandBracesLimited(pattern string, limits Limits) ([]string, error) {
+	tree := parseBraces(pattern)
+	count, bytes := tree.size()
+	bytes.Add(bytes, new(big.Int).Mul(count, big.NewInt(stringHeaderSize)))
+	if err := limits.checkBytes(count, bytes); err != nil {
+		return nil, err
+	}
+	return tree.expand(), nil
+}
+
+// CompressBraces folds the provided strings into a single brace pattern that
+// ExpandBraces expands back into the same strings, in the same order.
+//
+// The pattern is the common prefix and suffix of the strings surrounding a
+// brace expression for the parts that differ. When those parts are evenly
+// spaced integers or letters, a sequence expression is used; otherwise they
+// are listed as alternatives.
+//
+// If xs is empty, the empty string is returned.
+func CompressBraces(xs []string) string {
+	switch len(xs) {
+	case 0:
+		return ""
+	case 1:
+		return escapeBraces(xs[0])
+	}
+
+	prefix := xs[0]
+	for _, x := range xs[1:] {
+		prefix = prefix[:commonPrefixLen(prefix, x)]
+	}
+	prefix = trimCommonPrefix(prefix, xs[0][len(prefix):])
+
+	suffix := xs[0][len(prefix):]
+	for _, x := range xs[1:] {
+		suffix = suffix[len(suffix)-commonSuffixLen(suffix, x[len(prefix):]):]
+	}
+	suffix = trimCommonSuffix(suffix, xs[0][len(prefix):len(xs[0])-len(suffix)])
+
+	middles := make([]string, len(xs))
+	for i, x := range xs {
+		middles[i] = x[len(prefix) : len(x)-len(suffix)]
+	}
+
+	expr, ok := sequenceExpression(middles)
+	if !ok {
+		escaped := make([]string, len(middles))
+		for i, m := range middles {
+			escaped[i] = escapeBraces(m)
+		}
+		expr = "{" + strings.Join(escaped, ",") + "}"
+	}
+	return escapeBraces(prefix) + expr + escapeBraces(suffix)
+}
+
+// braceTree is a parsed brace pattern: literal text, followed by a brace
+// expression and the pattern after it unless rest is nil.
+type braceTree struct {
+	text         string
+	seq          *sequence
+	alternatives []*braceTree
+	rest         *braceTree
+}
+
+// braceMatch describes the brace expression opened at some position.
+type braceMatch struct {
+	end    int   // the position of the closing brace, or 0 if unmatched
+	commas []int // the positions of the commas separating alternatives
+	nested bool  // whether another brace is opened inside
+}
+
+// parseBraces parses pattern into a braceTree.
+func parseBraces(pattern string) *braceTree {
+	// Match every brace in a single pass, so that parsing is linear.
+	matches := make([]braceMatch, len(pattern))
+	var stack []int
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			if len(stack) > 0 {
+				matches[stack[len(stack)-1]].nested = true
+			}
+			stack = append(stack, i)
+		case ',':
+			if len(stack) > 0 {
+				open := stack[len(stack)-1]
+				matches[open].commas = append(matches[open].commas, i)
+			}
+		case '}':
+			if len(stack) > 0 {
+				matches[stack[len(stack)-1]].end = i
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return buildBraceTree(pattern, matches, 0, len(pattern))
+}
+
+// buildBraceTree builds the braceTree for pattern[lo:hi], within which every
+// matched brace is closed.
+func buildBraceTree(pattern string, matches []braceMatch, lo, hi int) *braceTree {
+	for i := lo; i < hi; i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			m := matches[i]
+			if m.end == 0 {
+				continue
+			}
+
+			tree := &braceTree{text: unescapeBraces(pattern[lo:i])}
+			if len(m.commas) > 0 {
+				start := i + 1
+				for _, end := range append(m.commas, m.end) {
+					tree.alternatives = append(tree.alternatives, buildBraceTree(pattern, matches, start, end))
+					start = end + 1
+				}
+			} else if seq, ok := parseSequence(pattern[i+1 : m.end]); ok && !m.nested {
+				tree.seq = &seq
+			} else {
+				// Braces that are not a valid expression are left as they
+				// are, but may contain one.
+				continue
+			}
+			tree.rest = buildBraceTree(pattern, matches, m.end+1, hi)
+			return tree
+		}
+	}
+	return &braceTree{text: unescapeBraces(pattern[lo:hi])}
+}
+
+// size returns the number of strings that the tree expands into and their
+// total length in bytes, without expanding it.
+func (t *braceTree) size() (count, bytes *big.Int) {
+	if t.rest == nil {
+		return big.NewInt(1), big.NewInt(int64(len(t.text)))
+	}
+
+	middles, middleBytes := new(big.Int), new(big.Int)
+	if t.seq != nil {
+		middles, middleBytes = t.seq.len(), t.seq.bytes()
+	} else {
+		for _, alternative := range t.alternatives {
+			c, b := alternative.size()
+			middles.Add(middles, c)
+			middleBytes.Add(middleBytes, b)
+		}
+	}
+	postscripts, postscriptBytes := t.rest.size()
+
+	// Every middle is paired with every postscript, behind the text.
+	count = new(big.Int).Mul(middles, postscripts)
+	bytes = new(big.Int).Mul(count, big.NewInt(int64(len(t.text))))
+	bytes.Add(bytes, middleBytes.Mul(middleBytes, postscripts))
+	bytes.Add(bytes, postscriptBytes.Mul(postscriptBytes, middles))
+	return count, bytes
+}
+
+// expand returns the strings that the tree expands into.
+func (t *braceTree) expand() []string {
+	var xs []string
+	t.walk(nil, func(b []byte) { xs = append(xs, string(b)) })
+	return xs
+}
+
+// walk calls fn with each expansion of the tree appended to buf. Each
+// expansion is only valid until fn returns.
+func (t *braceTree) walk(buf []byte, fn func([]byte)) {
+	buf = append(buf, t.text...)
+	switch {
+	case t.rest == nil:
+		fn(buf)
+	case t.seq != nil:
+		for _, x := range t.seq.expand() {
+			t.rest.walk(append(buf, x...), fn)
+		}
+	default:
+		for _, alternative := range t.alternatives {
+			alternative.walk(buf, func(b []byte) { t.rest.walk(b, fn) })
+		}
+	}
+}
+
+// sequence is a parsed sequence expression.
+type sequence struct {
+	x, y, step int
+	width      int
+	letters    bool
+}
+
+// parseSequence parses the body of a sequence expression, returning false if
+// it is not one.
+func parseSequence(body string) (sequence, bool) {
+	parts := strings.Split(body, "..")
+	if len(parts) != 2 && len(parts) != 3 {
+		return sequence{}, false
+	}
+
+	seq := sequence{step: 1}
+	if len(parts) == 3 {
+		step, err := strconv.Atoi(parts[2])
+		if err != nil || step == math.MinInt {
+			return sequence{}, false
+		}
+		if step < 0 {
+			step = -step
+		}
+		if step != 0 {
+			seq.step = step
+		}
+	}
+
+	if x, y, ok := sequenceLetters(parts[0], parts[1]); ok {
+		seq.x, seq.y, seq.letters = x, y, true
+		return seq, true
+	}
+
+	x, errX := strconv.Atoi(parts[0])
+	y, errY := strconv.Atoi(parts[1])
+	if errX != nil || errY != nil {
+		return sequence{}, false
+	}
+	seq.x, seq.y = x, y
+
+	if hasLeadingZero(parts[0]) || hasLeadingZero(parts[1]) {
+		seq.width = maxInt(len(parts[0]), len(parts[1]))
+	}
+	return seq, true
+}
+
+// len returns the number of values in the sequence.
+func (seq sequence) len() *big.Int {
+	n := new(big.Int).SetUint64(distance(seq.x, seq.y) / uint64(seq.step))
+	return n.Add(n, big.NewInt(1))
+}
+
+// bytes returns the total length of the values in the sequence.
+func (seq sequence) bytes() *big.Int {
+	n := seq.len()
+	if seq.letters {
+		return n
+	}
+
+	// The values are first, first+step, ..., in ascending order.
+	step := big.NewInt(int64(seq.step))
+	first := big.NewInt(int64(seq.x))
+	if seq.x > seq.y {
+		last := new(big.Int).Sub(n, big.NewInt(1))
+		first.Sub(first, last.Mul(last, step))
+	}
+
+	// Values with the same number of digits and sign have the same length.
+	total := new(big.Int)
+	add := func(lo, hi *big.Int, length int) {
+		terms := countTerms(first, step, n, lo, hi)
+		total.Add(total, terms.Mul(terms, big.NewInt(int64(maxInt(length, seq.width)))))
+	}
+	add(new(big.Int), new(big.Int), 1)
+	lo := big.NewInt(1)
+	for digits := 1; digits <= 19; digits++ {
+		next := new(big.Int).Mul(lo, big.NewInt(10))
+		hi := new(big.Int).Sub(next, big.NewInt(1))
+		add(lo, hi, digits)
+		add(new(big.Int).Neg(hi), new(big.Int).Neg(lo), digits+1)
+		lo = next
+	}
+	return total
+}
+
+// countTerms returns how many of the n terms first, first+step, ... are
+// within [lo, hi].
+func countTerms(first, step, n, lo, hi *big.Int) *big.Int {
+	if hi.Cmp(first) < 0 {
+		return new(big.Int)
+	}
+
+	// The terms within the range are those at indices from through to.
+	from := new(big.Int)
+	if lo.Cmp(first) > 0 {
+		from.Sub(lo, first)
+		from.Add(from, step).Sub(from, big.NewInt(1)).Quo(from, step)
+	}
+	to := new(big.Int).Sub(hi, first)
+	to.Quo(to, step)
+	if last := new(big.Int).Sub(n, big.NewInt(1)); to.Cmp(last) > 0 {
+		to = last
+	}
+
+	if from.Cmp(to) > 0 {
+		return new(big.Int)
+	}
+	return to.Sub(to, from).Add(to, big.NewInt(1))
+}
+
+// expand returns the values in the sequence.
+func (seq sequence) expand() []string {
+	var xs []string
+	for _, v := range steps(seq.x, seq.y, seq.step) {
+		if seq.letters {
+			xs = append(xs, string(rune(v)))
+		} else {
+			xs = append(xs, padInt(v, seq.width))
+		}
+	}
+	return xs
+}
+
+// sequenceLetters returns the endpoints of a letter sequence.
+func sequenceLetters(x, y string) (int, int, bool) {
+	isLetter := func(s string) bool {
+		return len(s) == 1 && (('a' <= s[0] && s[0] <= 'z') || ('A' <= s[0] && s[0] <= 'Z'))
+	}
+	if !isLetter(x) || !isLetter(y) {
+		return 0, 0, false
+	}
+	return int(x[0]), int(y[0]), true
+}
+
+// steps returns the values from x toward y, inclusive, in increments of step,
+// which must be positive.
+func steps(x, y, step int) []int {
+	vs := []int{x}
+	for v := x; distance(v, y) >= uint64(step); {
+		if x <= y {
+			v += step
+		} else {
+			v -= step
+		}
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+// distance returns |x-y| without overflowing.
+func distance(x, y int) uint64 {
+	if x <= y {
+		return uint64(y) - uint64(x)
+	}
+	return uint64(x) - uint64(y)
+}
+
+func hasLeadingZero(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	return len(s) > 1 && s[0] == '0'
+}
+
+// padInt formats v with zeros padding it to width, including any sign.
+func padInt(v, width int) string {
+	s := strconv.Itoa(v)
+	sign := ""
+	if v < 0 {
+		sign, s = "-", s[1:]
+	}
+	if pad := width - len(sign) - len(s); pad > 0 {
+		s = strings.Repeat("0", pad) + s
+	}
+	return sign + s
+}
+
+// sequenceExpression returns a sequence expression that expands into
+// exactly the provided strings, if there is one.
+func sequenceExpression(xs []string) (string, bool) {
+	if len(xs) < 3 {
+		return "", false
+	}
+
+	var (
+		values []int
+		format func(int) string
+	)
+	if _, _, ok := sequenceLetters(xs[0], xs[0]); ok {
+		format = func(v int) string { return string(rune(v)) }
+		for _, x := range xs {
+			v, _, ok := sequenceLetters(x, x)
+			if !ok {
+				return "", false
+			}
+			values = append(values, v)
+		}
+	} else {
+		width := 0
+		if hasLeadingZero(xs[0]) {
+			width = len(xs[0])
+		}
+		format = func(v int) string { return padInt(v, width) }
+		for _, x := range xs {
+			v, err := strconv.Atoi(x)
+			if err != nil {
+				return "", false
+			}
+			values = append(values, v)
+		}
+	}
+
+	step := values[1] - values[0]
+	if step == 0 {
+		return "", false
+	}
+	for i, v := range values {
+		if v != values[0]+i*step || format(v) != xs[i] {
+			return "", false
+		}
+	}
+
+	// Padded sequences must keep a fixed width, because ExpandBraces pads to
+	// the width of the wider endpoint.
+	if len(format(values[0])) != len(format(values[len(values)-1])) && hasLeadingZero(xs[0]) {
+		return "", false
+	}
+
+	first, last := format(values[0]), format(values[len(values)-1])
+	if step < 0 {
+		step = -step
+	}
+	if step == 1 {
+		return "{" + first + ".." + last + "}", true
+	}
+	return "{" + first + ".." + last + ".." + strconv.Itoa(step) + "}", true
+}
+
+// trimCommonPrefix shortens a common prefix so that it does not split a
+// multi-byte character, or a run of digits that continues into rest, the
+// remainder of a string that it prefixes. Keeping runs of digits whole allows
+// the parts that differ to be recognized as integers.
+func trimCommonPrefix(prefix, rest string) string {
+	splitsDigits := len(rest) > 0 && isDigit(rest[0])
+	for len(prefix) > 0 && (!utf8.ValidString(prefix) || (splitsDigits && isDigit(prefix[len(prefix)-1]))) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	return prefix
+}
+
+// trimCommonSuffix is trimCommonPrefix for a common suffix, where rest
+// precedes it.
+func trimCommonSuffix(suffix, rest string) string {
+	splitsDigits := len(rest) > 0 && isDigit(rest[len(rest)-1])
+	for len(suffix) > 0 && (!utf8.ValidString(suffix) || (splitsDigits && isDigit(suffix[0]))) {
+		suffix = suffix[1:]
+	}
+	return suffix
+}
+
+func isDigit(b byte) bool { return '0' <= b && b <= '9' }
+
+func commonPrefixLen(x, y string) int {
+	i := 0
+	for i < len(x) && i < len(y) && x[i] == y[i] {
+		i++
+	}
+	return i
+}
+
+func commonSuffixLen(x, y string) int {
+	i := 0
+	for i < len(x) && i < len(y) && x[len(x)-1-i] == y[len(y)-1-i] {
+		i++
+	}
+	return i
+}
+
+// escapeBraces escapes every character that ExpandBraces treats specially.
+func escapeBraces(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\', '{', '}', ',':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unescapeBraces removes the backslashes that escape characters.
+func unescapeBraces(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
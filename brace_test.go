@@ -0,0 +1,223 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExpandBraces(t *testing.T) {
+	table := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"", []string{""}},
+		{"plain", []string{"plain"}},
+		{"{a,b}", []string{"a", "b"}},
+		{"x{a,b}y", []string{"xay", "xby"}},
+		{"{a,b}{1,2}", []string{"a1", "a2", "b1", "b2"}},
+		{"host{1..3}.{us,eu}", []string{"host1.us", "host1.eu", "host2.us", "host2.eu", "host3.us", "host3.eu"}},
+		{"{a,b{c,d}}", []string{"a", "bc", "bd"}},
+		{"{,x}", []string{"", "x"}},
+		{"a{,}", []string{"a", "a"}},
+		{"{3..1}", []string{"3", "2", "1"}},
+		{"{1..10..3}", []string{"1", "4", "7", "10"}},
+		{"{10..1..-4}", []string{"10", "6", "2"}},
+		{"{08..11}", []string{"08", "09", "10", "11"}},
+		{"{1..010..4}", []string{"001", "005", "009"}},
+		{"{-2..1}", []string{"-2", "-1", "0", "1"}},
+		{"{-02..1}", []string{"-02", "-01", "000", "001"}},
+		{"{a..e..2}", []string{"a", "c", "e"}},
+		{"{C..A}", []string{"C", "B", "A"}},
+		{"{a}", []string{"{a}"}},
+		{"{}", []string{"{}"}},
+		{"{a..1}", []string{"{a..1}"}},
+		{"a{b", []string{"a{b"}},
+		{"{a{b,c}}", []string{"{ab}", "{ac}"}},
+		{`\{a,b\}`, []string{"{a,b}"}},
+		{`{a\,b,c}`, []string{"a,b", "c"}},
+		{`{a\}b,c}`, []string{"a}b", "c"}},
+		{"{9223372036854775800..9223372036854775807..3}", []string{"9223372036854775800", "9223372036854775803", "9223372036854775806"}},
+		{"{-9223372036854775801..-9223372036854775808..4}", []string{"-9223372036854775801", "-9223372036854775805"}},
+		{"{-9223372036854775808..9223372036854775807..9223372036854775807}", []string{"-9223372036854775808", "-1", "9223372036854775806"}},
+		{"{1..2..-9223372036854775808}", []string{"{1..2..-9223372036854775808}"}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.pattern, func(t *testing.T) {
+			actual := ExpandBraces(tt.pattern)
+			if !SliceEqual(actual, tt.expected) {
+				t.Errorf("actual = %q; want = %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandBracesLongPatterns(t *testing.T) {
+	literal := strings.Repeat("{c}", 20000)
+	table := []struct {
+		description string
+		pattern     string
+		expected    []string
+	}{
+		{"unmatched", strings.Repeat("{", 40000), []string{strings.Repeat("{", 40000)}},
+		{"nested invalid", strings.Repeat("{", 20000) + strings.Repeat("}", 20000), []string{strings.Repeat("{", 20000) + strings.Repeat("}", 20000)}},
+		{"chained", "{a,b}{a,b}" + literal, []string{"aa" + literal, "ab" + literal, "ba" + literal, "bb" + literal}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := ExpandBraces(tt.pattern)
+			if !SliceEqual(actual, tt.expected) {
+				t.Errorf("len(actual) = %d; want = %d", len(actual), len(tt.expected))
+			}
+		})
+	}
+}
+
+func TestExpandBracesLimited(t *testing.T) {
+	table := []struct {
+		pattern  string
+		expected string
+	}{
+		{"{1..9999999999}", "9999999999"},
+		{"{-9223372036854775808..9223372036854775807}", "18446744073709551616"},
+		{"{1..1024}{1..1024}{a,b}", "2097152"},
+		{"x{a,{1..2000}}{1..1000}", "2001000"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := ExpandBracesLimited(tt.pattern, Limits{MaxResults: 1 << 20})
+			var tooMany *TooManyResultsError
+			if !errors.As(err, &tooMany) {
+				t.Fatalf("err = %v; want = *TooManyResultsError", err)
+			}
+			if tooMany.Count.String() != tt.expected {
+				t.Errorf("Count = %s; want = %s", tooMany.Count, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandBracesLimitedBytes(t *testing.T) {
+	patterns := []string{
+		"",
+		"plain",
+		"x{a,b}y",
+		"{a,b{c,d}}z",
+		"{a}{1..3}",
+		`\{{1..3}\}`,
+		"{C..A}x",
+		"{-02..1}",
+		"{1..010..4}",
+		"{10..1..-4}{a,,b}",
+		"{-1000..1000..7}",
+		"{-05..120..3}",
+		"{120..-05..3}",
+		"{9223372036854775800..9223372036854775807..3}",
+		"{-9223372036854775801..-9223372036854775808..4}",
+		"{-9223372036854775808..9223372036854775807..9223372036854775807}",
+	}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			xs := ExpandBraces(pattern)
+			expected := int64(len(xs)) * stringHeaderSize
+			for _, x := range xs {
+				expected += int64(len(x))
+			}
+
+			actual, err := ExpandBracesLimited(pattern, Limits{MaxResults: uint64(len(xs)), MaxBytes: uint64(expected)})
+			if err != nil {
+				t.Fatalf("at limits err = %s", err)
+			}
+			if !SliceEqual(actual, xs) {
+				t.Errorf("actual = %q; want = %q", actual, xs)
+			}
+
+			_, err = ExpandBracesLimited(pattern, Limits{MaxBytes: uint64(expected - 1)})
+			var tooMany *TooManyResultsError
+			if !errors.As(err, &tooMany) {
+				t.Fatalf("err = %v; want = *TooManyResultsError", err)
+			}
+			if tooMany.Bytes.Int64() != expected {
+				t.Errorf("Bytes = %s; want = %d", tooMany.Bytes, expected)
+			}
+		})
+	}
+}
+
+func TestCompressBraces(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		expected    string
+	}{
+		{"empty", nil, ""},
+		{"single item", []string{"web{1}"}, `web\{1\}`},
+		{"range", []string{"host1.us", "host2.us", "host3.us"}, "host{1..3}.us"},
+		{"digits are kept whole", []string{"host10", "host11", "host12"}, "host{10..12}"},
+		{"padded range", []string{"n08", "n09", "n10"}, "n{08..10}"},
+		{"stepped range", []string{"p0", "p5", "p10"}, "p{0..10..5}"},
+		{"descending range", []string{"3", "2", "1"}, "{3..1}"},
+		{"letters", []string{"rack-a", "rack-b", "rack-c"}, "rack-{a..c}"},
+		{"alternatives", []string{"db.us", "db.eu"}, "db.{us,eu}"},
+		{"two numbers", []string{"web1", "web2"}, "web{1,2}"},
+		{"uneven numbers", []string{"web1", "web2", "web4"}, "web{1,2,4}"},
+		{"empty alternative", []string{"a", "ab"}, "a{,b}"},
+		{"duplicates", []string{"x", "x"}, "x{,}"},
+		{"special characters", []string{"a,b", "a{b"}, `a{\,,\{}b`},
+		{"multi-byte characters", []string{"é", "ê"}, "{é,ê}"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := CompressBraces(tt.xs)
+			if actual != tt.expected {
+				t.Errorf("actual = %q; want = %q", actual, tt.expected)
+			}
+			if len(tt.xs) > 0 {
+				if roundTrip := ExpandBraces(actual); !SliceEqual(roundTrip, tt.xs) {
+					t.Errorf("ExpandBraces(%q) = %q; want = %q", actual, roundTrip, tt.xs)
+				}
+			}
+		})
+	}
+}
+
+func TestCompressBracesRoundTrip(t *testing.T) {
+	patterns := []string{
+		"host{1..20}.example.com",
+		"{01..120}",
+		"{a,b,c}{1..3}",
+		"{x..z}-{1,5}",
+		`\{{1..3}\}`,
+		"{-5..5}",
+		"{098..101}",
+	}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			xs := ExpandBraces(pattern)
+			compressed := CompressBraces(xs)
+			if actual := ExpandBraces(compressed); !SliceEqual(actual, xs) {
+				t.Errorf("CompressBraces = %q expands to %s; want = %s", compressed, strings.Join(actual, " "), strings.Join(xs, " "))
+			}
+		})
+	}
+}
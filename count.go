@@ -0,0 +1,162 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import "math/big"
+
+// CountPermutations returns the number of permutations that
+// SlicePermutationsR produces for a pool of length n.
+//
+// Returns ErrOverflow if the count does not fit into a uint64.
+func CountPermutations(n, r int) (uint64, error) {
+	return toUint64(CountPermutationsBig(n, r))
+}
+
+// CountPermutationsBig is CountPermutations for counts that do not fit into a
+// uint64.
+func CountPermutationsBig(n, r int) *big.Int {
+	return permutationsBig(n, r)
+}
+
+// CountCombinations returns the number of combinations that
+// SliceCombinationsR produces for a pool of length n.
+//
+// Returns ErrOverflow if the count does not fit into a uint64.
+func CountCombinations(n, r int) (uint64, error) {
+	return toUint64(CountCombinationsBig(n, r))
+}
+
+// CountCombinationsBig is CountCombinations for counts that do not fit into a
+// uint64.
+func CountCombinationsBig(n, r int) *big.Int {
+	return binomialBig(n, r)
+}
+
+// CountCombinationsWithReplacement returns the number of combinations that
+// SliceCombinationsWithReplacement produces for a pool of length n.
+//
+// Returns ErrOverflow if the count does not fit into a uint64.
+func CountCombinationsWithReplacement(n, r int) (uint64, error) {
+	return toUint64(CountCombinationsWithReplacementBig(n, r))
+}
+
+// CountCombinationsWithReplacementBig is CountCombinationsWithReplacement for
+// counts that do not fit into a uint64.
+func CountCombinationsWithReplacementBig(n, r int) *big.Int {
+	return multichooseBig(n, r)
+}
+
+// CountProduct returns the number of tuples that SliceProduct produces for
+// pools of the provided lengths.
+//
+// Returns ErrOverflow if the count does not fit into a uint64.
+func CountProduct(lens ...int) (uint64, error) {
+	return toUint64(CountProductBig(lens...))
+}
+
+// CountProductBig is CountProduct for counts that do not fit into a uint64.
+func CountProductBig(lens ...int) *big.Int {
+	count := big.NewInt(1)
+	for _, n := range lens {
+		if n <= 0 {
+			return new(big.Int)
+		}
+		count.Mul(count, big.NewInt(int64(n)))
+	}
+	return count
+}
+
+// CountProductR returns the number of tuples that SliceProductR produces for
+// a pool of length n.
+//
+// Returns ErrOverflow if the count does not fit into a uint64.
+func CountProductR(n, r int) (uint64, error) {
+	return toUint64(CountProductRBig(n, r))
+}
+
+// CountProductRBig is CountProductR for counts that do not fit into a uint64.
+func CountProductRBig(n, r int) *big.Int {
+	if r < 0 || n < 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Exp(big.NewInt(int64(n)), big.NewInt(int64(r)), nil)
+}
+
+// CountPowersetRange returns the number of subsets that SlicePowersetRange
+// produces for a pool of length n.
+//
+// Returns ErrOverflow if the count does not fit into a uint64.
+func CountPowersetRange(n, min, max int) (uint64, error) {
+	return toUint64(CountPowersetRangeBig(n, min, max))
+}
+
+// CountPowersetRangeBig is CountPowersetRange for counts that do not fit into
+// a uint64.
+func CountPowersetRangeBig(n, min, max int) *big.Int {
+	if min < 0 {
+		min = 0
+	}
+	if max > n {
+		max = n
+	}
+
+	count := new(big.Int)
+	for r := min; r <= max; r++ {
+		count.Add(count, binomialBig(n, r))
+	}
+	return count
+}
+
+// toUint64 converts x into a uint64, returning ErrOverflow if it does not
+// fit.
+func toUint64(x *big.Int) (uint64, error) {
+	if !x.IsUint64() {
+		return 0, ErrOverflow
+	}
+	return x.Uint64(), nil
+}
+
+// permutationsBig returns the number of ways to arrange r of n distinct
+// items, n!/(n-r)!, or 0 if r is out of range.
+func permutationsBig(n, r int) *big.Int {
+	if r < 0 || r > n {
+		return new(big.Int)
+	}
+	return new(big.Int).MulRange(int64(n-r+1), int64(n))
+}
+
+// binomialBig returns the number of ways to choose r of n distinct items, or
+// 0 if r is out of range.
+func binomialBig(n, r int) *big.Int {
+	if r < 0 || r > n {
+		return new(big.Int)
+	}
+	return new(big.Int).Binomial(int64(n), int64(r))
+}
+
+// multichooseBig returns the number of ways to choose r of n distinct items
+// when items may be chosen more than once.
+func multichooseBig(n, r int) *big.Int {
+	if r < 0 || n < 0 {
+		return new(big.Int)
+	}
+	if n == 0 {
+		if r == 0 {
+			return big.NewInt(1)
+		}
+		return new(big.Int)
+	}
+	return binomialBig(n+r-1, r)
+}
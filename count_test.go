@@ -0,0 +1,141 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestCountsAgreeWithGenerators(t *testing.T) {
+	table := []struct {
+		description string
+		generate    func([]string, int) [][]string
+		count       func(int, int) (uint64, error)
+	}{
+		{"permutations", SlicePermutationsR, CountPermutations},
+		{"combinations", SliceCombinationsR, CountCombinations},
+		{"combinations with replacement", SliceCombinationsWithReplacement, CountCombinationsWithReplacement},
+		{"product", SliceProductR, CountProductR},
+	}
+
+	for _, tt := range table {
+		for n := 0; n <= len(letters); n++ {
+			for r := -1; r <= n+1; r++ {
+				t.Run(fmt.Sprintf("%s n = %d r = %d", tt.description, n, r), func(t *testing.T) {
+					expected := uint64(len(tt.generate(letters[:n], r)))
+					actual, err := tt.count(n, r)
+					if err != nil {
+						t.Fatalf("err = %s", err)
+					}
+					if actual != expected {
+						t.Errorf("actual = %d; want = %d", actual, expected)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestCountPowersetRange(t *testing.T) {
+	for n := 0; n <= len(letters); n++ {
+		for min := -1; min <= n+1; min++ {
+			for max := -1; max <= n+1; max++ {
+				expected := uint64(len(SlicePowersetRange(letters[:n], min, max)))
+				actual, err := CountPowersetRange(n, min, max)
+				if err != nil {
+					t.Fatalf("err = %s", err)
+				}
+				if actual != expected {
+					t.Errorf("n = %d min = %d max = %d: actual = %d; want = %d", n, min, max, actual, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestCountProduct(t *testing.T) {
+	table := []struct {
+		description string
+		lens        []int
+		expected    uint64
+	}{
+		{"no pools", nil, 1},
+		{"empty pool", []int{2, 0}, 0},
+		{"single pool", []int{3}, 3},
+		{"common case", []int{2, 3, 4}, 24},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			pools := make([][]string, len(tt.lens))
+			for i, n := range tt.lens {
+				pools[i] = letters[:n]
+			}
+			if generated := uint64(len(SliceProduct(pools...))); generated != tt.expected {
+				t.Fatalf("len(SliceProduct) = %d; want = %d", generated, tt.expected)
+			}
+
+			actual, err := CountProduct(tt.lens...)
+			if err != nil {
+				t.Fatalf("err = %s", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("actual = %d; want = %d", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCountOverflow(t *testing.T) {
+	table := []struct {
+		description string
+		count       func() (uint64, error)
+		big         *big.Int
+	}{
+		{"20! fits", func() (uint64, error) { return CountPermutations(20, 20) }, new(big.Int).MulRange(1, 20)},
+		{"21! overflows", func() (uint64, error) { return CountPermutations(21, 21) }, nil},
+		{"C(67, 33) fits", func() (uint64, error) { return CountCombinations(67, 33) }, new(big.Int).Binomial(67, 33)},
+		{"C(68, 34) overflows", func() (uint64, error) { return CountCombinations(68, 34) }, nil},
+		{"2^64 overflows", func() (uint64, error) { return CountProductR(2, 64) }, nil},
+		{"2^63 fits", func() (uint64, error) { return CountProductR(2, 63) }, new(big.Int).Lsh(big.NewInt(1), 63)},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual, err := tt.count()
+			if tt.big == nil {
+				if err != ErrOverflow {
+					t.Fatalf("err = %v; want = %v", err, ErrOverflow)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %s", err)
+			}
+			if actual != tt.big.Uint64() {
+				t.Errorf("actual = %d; want = %s", actual, tt.big)
+			}
+		})
+	}
+}
+
+func TestCountBig(t *testing.T) {
+	expected, _ := new(big.Int).SetString("28453041475240576740", 10)
+	if actual := CountCombinationsBig(68, 34); actual.Cmp(expected) != 0 {
+		t.Errorf("actual = %s; want = %s", actual, expected)
+	}
+}
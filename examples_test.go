@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"os"
+	"time"
 
 	"github.com/jzelinskie/stringz"
 )
@@ -68,3 +70,260 @@ func ExampleTrimPrefixIndex() {
 	// that
 	// that
 }
+
+func ExampleSliceCombinationsRIter() {
+	it := stringz.SliceCombinationsRIter([]string{"a", "b", "c", "d"}, 2)
+	for it.Next() {
+		combination := it.Value()
+		fmt.Println(combination)
+		if combination[1] == "c" {
+			break
+		}
+	}
+
+	// Output:
+	// [a b]
+	// [a c]
+}
+
+func ExampleSliceProduct() {
+	for _, env := range stringz.SliceProduct(
+		[]string{"dev", "prod"},
+		[]string{"us", "eu"},
+	) {
+		fmt.Println(env)
+	}
+
+	// Output:
+	// [dev us]
+	// [dev eu]
+	// [prod us]
+	// [prod eu]
+}
+
+func ExampleSlicePowerset() {
+	for _, flags := range stringz.SlicePowerset([]string{"beta", "debug"}) {
+		fmt.Println(flags)
+	}
+
+	// Output:
+	// []
+	// [beta]
+	// [debug]
+	// [beta debug]
+}
+
+func ExampleUnrankCombination() {
+	pool := []string{"a", "b", "c", "d"}
+
+	// Jump straight to the fourth combination.
+	combination, err := stringz.UnrankCombination(pool, 2, 3)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(combination)
+
+	rank, err := stringz.RankCombination(pool, combination)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(rank)
+
+	// Output:
+	// [b c]
+	// 3
+}
+
+func ExampleSampleDistinctCombinations() {
+	rng := rand.New(rand.NewSource(42))
+	pool := []string{"a", "b", "c", "d", "e", "f"}
+
+	sample, err := stringz.SampleDistinctCombinations(rng, pool, 3, 20)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(len(sample), sample[0], sample[19])
+
+	// Output:
+	// 20 [a b c] [d e f]
+}
+
+func ExampleShards() {
+	pool := []string{"a", "b", "c", "d"}
+
+	// Every process computes the same boundaries and walks only its own.
+	shards := stringz.Shards(stringz.CountCombinationsBig(len(pool), 2), 3)
+	for _, shard := range shards {
+		it := stringz.SliceCombinationsRShardIter(pool, 2, shard)
+		for it.Next() {
+			fmt.Println(shard.Index, it.Value())
+		}
+	}
+
+	// Output:
+	// 0 [a b]
+	// 0 [a c]
+	// 1 [a d]
+	// 1 [b c]
+	// 2 [b d]
+	// 2 [c d]
+}
+
+func ExampleSlicePartitionsK() {
+	for _, teams := range stringz.SlicePartitionsK([]string{"ann", "bob", "cat"}, 2) {
+		fmt.Println(teams)
+	}
+
+	// Output:
+	// [[ann bob] [cat]]
+	// [[ann cat] [bob]]
+	// [[ann] [bob cat]]
+}
+
+func ExampleCombinationsR() {
+	for _, pair := range stringz.CombinationsR([]int{1, 2, 3}, 2) {
+		fmt.Println(pair[0] + pair[1])
+	}
+
+	// Output:
+	// 3
+	// 4
+	// 5
+}
+
+func ExampleSliceMinimalChangePermutations() {
+	it := stringz.SliceMinimalChangePermutations([]string{"a", "b", "c"})
+	for it.Next() {
+		i, j := it.Swapped()
+		fmt.Println(it.Value(), i, j)
+	}
+
+	// Output:
+	// [a b c] -1 -1
+	// [a c b] 1 2
+	// [c a b] 0 1
+	// [c b a] 1 2
+	// [b c a] 0 1
+	// [b a c] 1 2
+}
+
+func ExampleNextPermutation() {
+	xs := []string{"a", "a", "b"}
+	for more := true; more; more = stringz.NextPermutation(xs) {
+		fmt.Println(xs)
+	}
+
+	// Output:
+	// [a a b]
+	// [a b a]
+	// [b a a]
+}
+
+func ExampleParsePattern() {
+	p, err := stringz.ParsePattern("node-[ab]?d")
+	if err != nil {
+		panic(err)
+	}
+
+	count, err := p.Count()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(count)
+
+	for it := p.Iter(); it.Next(); {
+		fmt.Println(it.Value())
+		if it.Value() == "node-a2" {
+			break
+		}
+	}
+
+	// Output:
+	// 20
+	// node-a0
+	// node-a1
+	// node-a2
+}
+
+func ExampleExpandBraces() {
+	for _, host := range stringz.ExpandBraces("web{01..03}.{us,eu}") {
+		fmt.Println(host)
+	}
+
+	// Output:
+	// web01.us
+	// web01.eu
+	// web02.us
+	// web02.eu
+	// web03.us
+	// web03.eu
+}
+
+func ExampleCompressBraces() {
+	fmt.Println(stringz.CompressBraces([]string{"db1.us", "db2.us", "db3.us"}))
+
+	// Output:
+	// db{1..3}.us
+}
+
+func ExampleParseRangeList() {
+	ranges, err := stringz.ParseRangeList("9-11,1-5,7,4-6")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(ranges)
+
+	formatted, err := stringz.FormatRangeList(ranges)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(formatted)
+
+	// Output:
+	// [{1 7} {9 11}]
+	// 1-7,9-11
+}
+
+func ExampleSplitExactAny() {
+	var (
+		host    string
+		port    uint16
+		timeout time.Duration
+	)
+	if err := stringz.SplitExactAny("db.internal:5432:3s", ":", &host, &port, &timeout); err != nil {
+		panic(err)
+	}
+	fmt.Println(host, port, timeout)
+
+	// Output:
+	// db.internal 5432 3s
+}
+
+func ExampleSplitQuoted() {
+	fields, err := stringz.SplitQuoted(`name="Doe, Jane",age=3`, ",", stringz.DefaultQuoteOptions)
+	if err != nil {
+		panic(err)
+	}
+	for _, field := range fields {
+		fmt.Println(field)
+	}
+
+	// Output:
+	// name=Doe, Jane
+	// age=3
+}
+
+func ExampleSplitExactDefault() {
+	for _, addr := range []string{"db.internal", "db.internal:5433", "db.internal::udp"} {
+		var host, port, proto string
+		if err := stringz.SplitExactDefault(addr, ":", 1, []string{"5432", "tcp"}, &host, &port, &proto); err != nil {
+			panic(err)
+		}
+		fmt.Println(host, port, proto)
+	}
+
+	// Output:
+	// db.internal 5432 tcp
+	// db.internal 5433 tcp
+	// db.internal 5432 udp
+}
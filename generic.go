@@ -0,0 +1,82 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+// Permutations is SlicePermutations for slices of any type.
+func Permutations[T any](pool []T) [][]T {
+	return PermutationsR(pool, len(pool))
+}
+
+// PermutationsR is SlicePermutationsR for slices of any type.
+//
+// The permutations are produced by the same algorithm and in the same order
+// as SlicePermutationsR.
+func PermutationsR[T any](pool []T, r int) [][]T {
+	if r < 0 || r > len(pool) {
+		return nil
+	}
+
+	p := newPermutationIndices(len(pool), r)
+	return collectIndices(pool, p.indices, func([]int) bool {
+		return p.next()
+	})
+}
+
+// CombinationsR is SliceCombinationsR for slices of any type.
+//
+// The combinations are produced by the same algorithm and in the same order
+// as SliceCombinationsR.
+func CombinationsR[T any](pool []T, r int) [][]T {
+	if r < 0 || r > len(pool) {
+		return nil
+	}
+
+	n := len(pool)
+	indices := make([]int, r)
+	for i := range indices {
+		indices[i] = i
+	}
+	return collectIndices(pool, indices, func(indices []int) bool {
+		return nextCombination(indices, n)
+	})
+}
+
+// CombinationsWithReplacement is SliceCombinationsWithReplacement for slices
+// of any type.
+//
+// The combinations are produced by the same algorithm and in the same order
+// as SliceCombinationsWithReplacement.
+func CombinationsWithReplacement[T any](pool []T, r int) [][]T {
+	if r < 0 || (r > 0 && len(pool) == 0) {
+		return nil
+	}
+
+	n := len(pool)
+	return collectIndices(pool, make([]int, r), func(indices []int) bool {
+		return nextCombinationWithReplacement(indices, n)
+	})
+}
+
+// collectIndices returns the values of pool found at indices, and at every
+// successive set of indices that next advances it to.
+func collectIndices[T any](pool []T, indices []int, next func([]int) bool) [][]T {
+	var ys [][]T
+	for {
+		ys = append(ys, pick(pool, indices))
+		if !next(indices) {
+			return ys
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestGenericMatchesString(t *testing.T) {
+	type host struct {
+		id   int
+		name string
+	}
+	hosts := make([]host, 4)
+	names := make([]string, len(hosts))
+	for i := range hosts {
+		hosts[i] = host{i, strconv.Itoa(i)}
+		names[i] = hosts[i].name
+	}
+
+	table := []struct {
+		description string
+		generic     func([]host, int) [][]host
+		str         func([]string, int) [][]string
+	}{
+		{"permutations", PermutationsR[host], SlicePermutationsR},
+		{"combinations", CombinationsR[host], SliceCombinationsR},
+		{"combinations with replacement", CombinationsWithReplacement[host], SliceCombinationsWithReplacement},
+	}
+
+	for _, tt := range table {
+		for r := -1; r <= len(hosts)+1; r++ {
+			t.Run(fmt.Sprintf("%s r = %d", tt.description, r), func(t *testing.T) {
+				generic := tt.generic(hosts, r)
+				expected := tt.str(names, r)
+				if len(generic) != len(expected) {
+					t.Fatalf("len(actual) = %d; want = %d", len(generic), len(expected))
+				}
+				for i, tuple := range generic {
+					actual := make([]string, len(tuple))
+					for j, h := range tuple {
+						actual[j] = h.name
+					}
+					if !SliceEqual(actual, expected[i]) {
+						t.Fatalf("actual[%d] = %v; want = %v", i, actual, expected[i])
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	actual := Permutations([]int{1, 2, 3})
+	expected := [][]int{{1, 2, 3}, {1, 3, 2}, {2, 1, 3}, {2, 3, 1}, {3, 1, 2}, {3, 2, 1}}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("actual = %v; want = %v", actual, expected)
+	}
+}
@@ -0,0 +1,737 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+// Iterator lazily produces a sequence of string slices.
+//
+// An Iterator is used like a bufio.Scanner: call Next to advance it and Value
+// to read the slice it advanced to. Abandoning an Iterator before it has been
+// exhausted is safe.
+type Iterator struct {
+	next  func() ([]string, bool)
+	value []string
+}
+
+// Next advances the Iterator to its next value. It returns false once the
+// sequence has been exhausted.
+func (it *Iterator) Next() bool {
+	if it.next == nil {
+		return false
+	}
+
+	v, ok := it.next()
+	if !ok {
+		it.next, it.value = nil, nil
+		return false
+	}
+	it.value = v
+	return true
+}
+
+// Value returns the value produced by the most recent call to Next.
+//
+// Every value is newly allocated, so it is safe to retain.
+func (it *Iterator) Value() []string { return it.value }
+
+// collect exhausts an Iterator into a slice.
+func collect(it *Iterator) [][]string {
+	var ys [][]string
+	for it.Next() {
+		ys = append(ys, it.Value())
+	}
+	return ys
+}
+
+// SlicePermutationsRIter is the lazy form of SlicePermutationsR.
+//
+// The permutations are produced one at a time in the same order that
+// SlicePermutationsR returns them.
+func SlicePermutationsRIter(pool []string, r int) *Iterator {
+	if r < 0 || r > len(pool) {
+		return &Iterator{}
+	}
+	return permutationsIter(pool, r)
+}
+
+// SliceCombinationsRIter is the lazy form of SliceCombinationsR.
+//
+// The combinations are produced one at a time in the same order that
+// SliceCombinationsR returns them.
+func SliceCombinationsRIter(pool []string, r int) *Iterator {
+	if r < 0 || r > len(pool) {
+		return &Iterator{}
+	}
+	return combinationsIter(pool, r)
+}
+
+// SliceCombinationsWithReplacementIter is the lazy form of
+// SliceCombinationsWithReplacement.
+//
+// The combinations are produced one at a time in the same order that
+// SliceCombinationsWithReplacement returns them.
+func SliceCombinationsWithReplacementIter(pool []string, r int) *Iterator {
+	if r < 0 || (r > 0 && len(pool) == 0) {
+		return &Iterator{}
+	}
+	return combinationsWithReplacementIter(pool, r)
+}
+
+// SliceProduct returns the Cartesian product of the provided string slices.
+//
+// If any of the pools is empty, nil is returned. If no pools are provided, a
+// single empty tuple is returned.
+//
+// The product tuples are emitted in lexicographic ordering according to the
+// order of the input iterables. So, if each input iterable is sorted, the
+// product tuples will be produced in sorted order.
+//
+// This matches the behavior of Python's itertools library:
+// itertools.product(*iterables)
+func SliceProduct(pools ...[]string) [][]string {
+	return collect(SliceProductIter(pools...))
+}
+
+// SliceProductIter is the lazy form of SliceProduct.
+func SliceProductIter(pools ...[]string) *Iterator {
+	return productIter(pools)
+}
+
+// SliceProductR returns the Cartesian product of the provided string slice
+// with itself r times.
+//
+// It is equivalent to `SliceProduct(pool, pool, ...)` with r copies of pool.
+//
+// If r is less than 0, or the pool is empty and r is larger than 0, nil is
+// returned. If r is 0, a single empty tuple is returned.
+//
+// This matches the behavior of Python's itertools library:
+// itertools.product(iterable, repeat=r)
+func SliceProductR(pool []string, r int) [][]string {
+	return collect(SliceProductRIter(pool, r))
+}
+
+// SliceProductRIter is the lazy form of SliceProductR.
+func SliceProductRIter(pool []string, r int) *Iterator {
+	if r < 0 {
+		return &Iterator{}
+	}
+	return productIter(repeatPool(pool, r))
+}
+
+// repeatPool returns a slice containing r references to pool.
+func repeatPool(pool []string, r int) [][]string {
+	pools := make([][]string, r)
+	for i := range pools {
+		pools[i] = pool
+	}
+	return pools
+}
+
+func productIter(pools [][]string) *Iterator {
+	for _, pool := range pools {
+		if len(pool) == 0 {
+			return &Iterator{}
+		}
+	}
+
+	indices := make([]int, len(pools))
+	started := false
+	return &Iterator{next: func() ([]string, bool) {
+		if started && !nextProduct(indices, pools) {
+			return nil, false
+		}
+		started = true
+
+		y := make([]string, len(indices))
+		for i, j := range indices {
+			y[i] = pools[i][j]
+		}
+		return y, true
+	}}
+}
+
+// SlicePowerset returns every subset of the provided string slice, including
+// the empty subset.
+//
+// It is equivalent to `SlicePowersetRange(pool, 0, len(pool))`.
+func SlicePowerset(pool []string) [][]string {
+	return SlicePowersetRange(pool, 0, len(pool))
+}
+
+// SlicePowersetRange returns every subset of the provided string slice that
+// has at least min and at most max elements.
+//
+// A min less than 0 is treated as 0 and a max larger than the length of the
+// pool is treated as the length of the pool. If min is larger than max after
+// this adjustment, nil is returned.
+//
+// The subsets are emitted ordered by their size and then in the order that
+// SliceCombinationsR produces them for that size. The empty subset is
+// represented by an empty, non-nil slice.
+//
+// Elements are treated as unique based on their position, not on their value.
+func SlicePowersetRange(pool []string, min, max int) [][]string {
+	return collect(SlicePowersetRangeIter(pool, min, max))
+}
+
+// SlicePowersetIter is the lazy form of SlicePowerset.
+func SlicePowersetIter(pool []string) *Iterator {
+	return SlicePowersetRangeIter(pool, 0, len(pool))
+}
+
+// SlicePowersetRangeIter is the lazy form of SlicePowersetRange.
+func SlicePowersetRangeIter(pool []string, min, max int) *Iterator {
+	if min < 0 {
+		min = 0
+	}
+	if max > len(pool) {
+		max = len(pool)
+	}
+
+	r := min
+	current := &Iterator{}
+	return &Iterator{next: func() ([]string, bool) {
+		for !current.Next() {
+			if r > max {
+				return nil, false
+			}
+			current = combinationsIter(pool, r)
+			r++
+		}
+		return current.Value(), true
+	}}
+}
+
+// SliceMultisetPermutationsR returns successive r-length permutations of the
+// distinct values in the provided string slice, where each value may be used
+// as many times as it appears in the pool.
+//
+// If r is less than 0 or larger than the length of the pool, nil is returned.
+// If r is 0, a single empty tuple is returned.
+//
+// Unlike SlicePermutationsR, elements are treated as unique based on their
+// value, so each distinct permutation is emitted exactly once and no
+// duplicates are generated along the way.
+//
+// The permutation tuples are emitted in lexicographic ordering according to
+// the order in which each value first appears in the input iterable. So, if
+// the input iterable is sorted, the permutation tuples will be produced in
+// sorted order.
+func SliceMultisetPermutationsR(pool []string, r int) [][]string {
+	return collect(SliceMultisetPermutationsRIter(pool, r))
+}
+
+// SliceMultisetPermutationsRIter is the lazy form of
+// SliceMultisetPermutationsR.
+func SliceMultisetPermutationsRIter(pool []string, r int) *Iterator {
+	if r < 0 || r > len(pool) {
+		return &Iterator{}
+	}
+	return multisetIter(pool, r, false)
+}
+
+// SliceMultisetCombinationsR returns r-length subsequences of the distinct
+// values in the provided string slice, where each value may be used as many
+// times as it appears in the pool.
+//
+// If r is less than 0 or larger than the length of the pool, nil is returned.
+// If r is 0, a single empty tuple is returned.
+//
+// Unlike SliceCombinationsR, elements are treated as unique based on their
+// value, so each distinct combination is emitted exactly once and no
+// duplicates are generated along the way.
+//
+// The combination tuples are emitted in lexicographic ordering according to
+// the order in which each value first appears in the input iterable. So, if
+// the input iterable is sorted, the combination tuples will be produced in
+// sorted order.
+func SliceMultisetCombinationsR(pool []string, r int) [][]string {
+	return collect(SliceMultisetCombinationsRIter(pool, r))
+}
+
+// SliceMultisetCombinationsRIter is the lazy form of
+// SliceMultisetCombinationsR.
+func SliceMultisetCombinationsRIter(pool []string, r int) *Iterator {
+	if r < 0 || r > len(pool) {
+		return &Iterator{}
+	}
+	return multisetIter(pool, r, true)
+}
+
+// multisetIter walks the r-length tuples of the distinct values of pool, only
+// ever placing a value while copies of it remain. If nondecreasing is true,
+// values are never placed before a value that preceded them in the pool,
+// which yields combinations rather than permutations.
+func multisetIter(pool []string, r int, nondecreasing bool) *Iterator {
+	values := Dedup(pool)
+	remaining := make([]int, len(values))
+	for _, x := range pool {
+		remaining[SliceIndex(values, x)]++
+	}
+
+	b := &backtracker{
+		n:       len(values),
+		indices: make([]int, r),
+		accept:  func(prefix []int, i int) bool { return remaining[i] > 0 },
+		place:   func(i int) { remaining[i]-- },
+		remove:  func(i int) { remaining[i]++ },
+	}
+	if nondecreasing {
+		b.lowest = func(prefix []int) int {
+			if len(prefix) == 0 {
+				return 0
+			}
+			return prefix[len(prefix)-1]
+		}
+	}
+	return backtrackIter(values, b)
+}
+
+// SlicePermutationsRVisit calls fn with each permutation that
+// SlicePermutationsR produces, in the same order, until fn returns false.
+//
+// Every permutation is written into buf, which is only reallocated if its
+// capacity is smaller than r, so no memory is allocated per permutation. The
+// slice passed to fn is overwritten by the next permutation, so fn must copy
+// it in order to retain it.
+func SlicePermutationsRVisit(pool []string, r int, buf []string, fn func([]string) bool) {
+	if r < 0 || r > len(pool) {
+		return
+	}
+
+	p := newPermutationIndices(len(pool), r)
+	visitIndices(pool, p.indices, func([]int) bool {
+		return p.next()
+	}, buf, fn)
+}
+
+// SliceCombinationsRVisit calls fn with each combination that
+// SliceCombinationsR produces, in the same order, until fn returns false.
+//
+// Every combination is written into buf, which is only reallocated if its
+// capacity is smaller than r, so no memory is allocated per combination. The
+// slice passed to fn is overwritten by the next combination, so fn must copy
+// it in order to retain it.
+func SliceCombinationsRVisit(pool []string, r int, buf []string, fn func([]string) bool) {
+	if r < 0 || r > len(pool) {
+		return
+	}
+
+	n := len(pool)
+	indices := make([]int, r)
+	for i := range indices {
+		indices[i] = i
+	}
+	visitIndices(pool, indices, func(indices []int) bool {
+		return nextCombination(indices, n)
+	}, buf, fn)
+}
+
+// SliceCombinationsWithReplacementVisit calls fn with each combination that
+// SliceCombinationsWithReplacement produces, in the same order, until fn
+// returns false.
+//
+// Every combination is written into buf, which is only reallocated if its
+// capacity is smaller than r, so no memory is allocated per combination. The
+// slice passed to fn is overwritten by the next combination, so fn must copy
+// it in order to retain it.
+func SliceCombinationsWithReplacementVisit(pool []string, r int, buf []string, fn func([]string) bool) {
+	if r < 0 || (r > 0 && len(pool) == 0) {
+		return
+	}
+
+	n := len(pool)
+	visitIndices(pool, make([]int, r), func(indices []int) bool {
+		return nextCombinationWithReplacement(indices, n)
+	}, buf, fn)
+}
+
+// SlicePermutationsRPruned returns the permutations that SlicePermutationsR
+// produces, in the same order, skipping every permutation that begins with a
+// partial permutation for which valid returns false.
+//
+// valid is called with each partial permutation as it is built, so rejecting
+// one prunes every permutation that extends it without generating them. The
+// slice passed to valid is reused, so it must not be retained.
+func SlicePermutationsRPruned(pool []string, r int, valid func(prefix []string) bool) [][]string {
+	return collect(SlicePermutationsRPrunedIter(pool, r, valid))
+}
+
+// SlicePermutationsRPrunedIter is the lazy form of SlicePermutationsRPruned.
+func SlicePermutationsRPrunedIter(pool []string, r int, valid func(prefix []string) bool) *Iterator {
+	if r < 0 || r > len(pool) {
+		return &Iterator{}
+	}
+
+	used := make([]bool, len(pool))
+	b := prunedBacktracker(pool, r, valid)
+	accept := b.accept
+	b.accept = func(prefix []int, i int) bool { return !used[i] && accept(prefix, i) }
+	b.place = func(i int) { used[i] = true }
+	b.remove = func(i int) { used[i] = false }
+	return backtrackIter(pool, b)
+}
+
+// SliceCombinationsRPruned returns the combinations that SliceCombinationsR
+// produces, in the same order, skipping every combination that begins with a
+// partial combination for which valid returns false.
+//
+// valid is called with each partial combination as it is built, so rejecting
+// one prunes every combination that extends it without generating them. The
+// slice passed to valid is reused, so it must not be retained.
+func SliceCombinationsRPruned(pool []string, r int, valid func(prefix []string) bool) [][]string {
+	return collect(SliceCombinationsRPrunedIter(pool, r, valid))
+}
+
+// SliceCombinationsRPrunedIter is the lazy form of SliceCombinationsRPruned.
+func SliceCombinationsRPrunedIter(pool []string, r int, valid func(prefix []string) bool) *Iterator {
+	if r < 0 || r > len(pool) {
+		return &Iterator{}
+	}
+
+	b := prunedBacktracker(pool, r, valid)
+	accept := b.accept
+	b.accept = func(prefix []int, i int) bool {
+		// Skip positions that leave too few positions to complete the tuple.
+		return i+r-len(prefix) <= len(pool) && accept(prefix, i)
+	}
+	b.lowest = func(prefix []int) int {
+		if len(prefix) == 0 {
+			return 0
+		}
+		return prefix[len(prefix)-1] + 1
+	}
+	return backtrackIter(pool, b)
+}
+
+// SliceCombinationsWithReplacementPruned returns the combinations that
+// SliceCombinationsWithReplacement produces, in the same order, skipping
+// every combination that begins with a partial combination for which valid
+// returns false.
+//
+// valid is called with each partial combination as it is built, so rejecting
+// one prunes every combination that extends it without generating them. The
+// slice passed to valid is reused, so it must not be retained.
+func SliceCombinationsWithReplacementPruned(pool []string, r int, valid func(prefix []string) bool) [][]string {
+	return collect(SliceCombinationsWithReplacementPrunedIter(pool, r, valid))
+}
+
+// SliceCombinationsWithReplacementPrunedIter is the lazy form of
+// SliceCombinationsWithReplacementPruned.
+func SliceCombinationsWithReplacementPrunedIter(pool []string, r int, valid func(prefix []string) bool) *Iterator {
+	if r < 0 || (r > 0 && len(pool) == 0) {
+		return &Iterator{}
+	}
+
+	b := prunedBacktracker(pool, r, valid)
+	b.lowest = func(prefix []int) int {
+		if len(prefix) == 0 {
+			return 0
+		}
+		return prefix[len(prefix)-1]
+	}
+	return backtrackIter(pool, b)
+}
+
+// prunedBacktracker returns a backtracker over r-length tuples of positions
+// in pool that only accepts a position if valid accepts the values of the
+// tuple it would extend.
+func prunedBacktracker(pool []string, r int, valid func(prefix []string) bool) *backtracker {
+	values := make([]string, r)
+	return &backtracker{
+		n:       len(pool),
+		indices: make([]int, r),
+		accept: func(prefix []int, i int) bool {
+			values[len(prefix)] = pool[i]
+			return valid(values[:len(prefix)+1])
+		},
+	}
+}
+
+func permutationsIter(pool []string, r int) *Iterator {
+	p := newPermutationIndices(len(pool), r)
+	return indexIterator(pool, p.indices, func([]int) bool {
+		return p.next()
+	})
+}
+
+func combinationsIter(pool []string, r int) *Iterator {
+	n := len(pool)
+	indices := make([]int, r)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indexIterator(pool, indices, func(indices []int) bool {
+		return nextCombination(indices, n)
+	})
+}
+
+func combinationsWithReplacementIter(pool []string, r int) *Iterator {
+	n := len(pool)
+	return indexIterator(pool, make([]int, r), func(indices []int) bool {
+		return nextCombinationWithReplacement(indices, n)
+	})
+}
+
+// indexIterator returns an Iterator over the values of pool found at indices,
+// which is advanced in place by next after every value is produced.
+func indexIterator(pool []string, indices []int, next func([]int) bool) *Iterator {
+	started := false
+	return &Iterator{next: func() ([]string, bool) {
+		if started && !next(indices) {
+			return nil, false
+		}
+		started = true
+		return pick(pool, indices), true
+	}}
+}
+
+// backtrackIter returns an Iterator over the values of pool found at each
+// tuple of positions that the backtracker reaches.
+func backtrackIter(pool []string, b *backtracker) *Iterator {
+	return &Iterator{next: func() ([]string, bool) {
+		if !b.next() {
+			return nil, false
+		}
+		return pick(pool, b.indices), true
+	}}
+}
+
+// visitIndices writes the values of pool found at indices into buf and calls
+// fn with it, advancing indices with next until either returns false.
+func visitIndices(pool []string, indices []int, next func([]int) bool, buf []string, fn func([]string) bool) {
+	if cap(buf) < len(indices) {
+		buf = make([]string, len(indices))
+	}
+	buf = buf[:len(indices)]
+
+	for {
+		for i, j := range indices {
+			buf[i] = pool[j]
+		}
+		if !fn(buf) || !next(indices) {
+			return
+		}
+	}
+}
+
+// pick returns a new slice of the values of pool found at indices.
+func pick[T any](pool []T, indices []int) []T {
+	y := make([]T, len(indices))
+	for i, j := range indices {
+		y[i] = pool[j]
+	}
+	return y
+}
+
+// nextCombination advances indices to the lexicographically next
+// r-combination of n positions. It returns false when there is none.
+func nextCombination(indices []int, n int) bool {
+	r := len(indices)
+
+	i := r - 1
+	for i >= 0 && indices[i] == i+n-r {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+
+	indices[i]++
+	for j := i + 1; j < r; j++ {
+		indices[j] = indices[j-1] + 1
+	}
+	return true
+}
+
+// nextCombinationWithReplacement advances indices to the lexicographically
+// next r-combination of n positions in which positions may repeat. It returns
+// false when there is none.
+func nextCombinationWithReplacement(indices []int, n int) bool {
+	r := len(indices)
+
+	i := r - 1
+	for i >= 0 && indices[i] == n-1 {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+
+	v := indices[i] + 1
+	for j := i; j < r; j++ {
+		indices[j] = v
+	}
+	return true
+}
+
+// nextProduct advances indices like an odometer, where each index wraps
+// around at the length of its corresponding pool. It returns false when every
+// index has wrapped.
+func nextProduct(indices []int, pools [][]string) bool {
+	for i := len(indices) - 1; i >= 0; i-- {
+		indices[i]++
+		if indices[i] < len(pools[i]) {
+			return true
+		}
+		indices[i] = 0
+	}
+	return false
+}
+
+// permutationIndices tracks an r-permutation of n positions along with which
+// of those positions are in use, so that it can be advanced without
+// allocating.
+type permutationIndices struct {
+	indices []int
+	used    []bool
+}
+
+func newPermutationIndices(n, r int) *permutationIndices {
+	p := &permutationIndices{indices: make([]int, r), used: make([]bool, n)}
+	for i := range p.indices {
+		p.indices[i] = i
+		p.used[i] = true
+	}
+	return p
+}
+
+// next advances the indices to the lexicographically next r-permutation. It
+// returns false when there is none.
+func (p *permutationIndices) next() bool {
+	indices := p.indices
+	n, r := len(p.used), len(indices)
+	for i := r - 1; i >= 0; i-- {
+		p.used[indices[i]] = false
+		for v := indices[i] + 1; v < n; v++ {
+			if p.used[v] {
+				continue
+			}
+			indices[i] = v
+			p.used[v] = true
+
+			// Fill the remainder with the smallest unused positions.
+			j := i + 1
+			for u := 0; j < r; u++ {
+				if !p.used[u] {
+					indices[j] = u
+					p.used[u] = true
+					j++
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// backtracker lazily walks the tree of r-length tuples of positions in
+// [0, n), reaching the complete tuples in lexicographic order.
+//
+// A position is only placed at the end of a partial tuple if accept reports
+// true, so rejecting a partial tuple prunes every tuple that extends it.
+type backtracker struct {
+	n       int
+	indices []int
+
+	// lowest returns the first position to consider after the prefix. If it
+	// is nil, every position is considered.
+	lowest func(prefix []int) int
+
+	// accept reports whether position i may follow the prefix.
+	accept func(prefix []int, i int) bool
+
+	// place and remove, if not nil, are called as positions are appended to
+	// and removed from the end of the tuple.
+	place, remove func(i int)
+
+	candidates []int
+	depth      int
+	started    bool
+	done       bool
+}
+
+// next advances indices to the next complete tuple. It returns false when
+// there is none.
+func (b *backtracker) next() bool {
+	if b.done {
+		return false
+	}
+
+	r := len(b.indices)
+	if !b.started {
+		b.started = true
+		if r == 0 {
+			return true
+		}
+		b.candidates = make([]int, r)
+		b.candidates[0] = b.first(0)
+	} else {
+		if r == 0 {
+			b.done = true
+			return false
+		}
+		b.pop()
+	}
+
+	for b.depth >= 0 {
+		placed := false
+		for i := b.candidates[b.depth]; i < b.n; i++ {
+			if b.accept(b.indices[:b.depth], i) {
+				b.indices[b.depth] = i
+				if b.place != nil {
+					b.place(i)
+				}
+				placed = true
+				break
+			}
+		}
+
+		if !placed {
+			b.depth--
+			if b.depth >= 0 {
+				b.pop()
+			}
+			continue
+		}
+
+		if b.depth == r-1 {
+			return true
+		}
+		b.depth++
+		b.candidates[b.depth] = b.first(b.depth)
+	}
+
+	b.done = true
+	return false
+}
+
+// first returns the first position to consider at depth.
+func (b *backtracker) first(depth int) int {
+	if b.lowest == nil {
+		return 0
+	}
+	return b.lowest(b.indices[:depth])
+}
+
+// pop removes the position at the current depth so that the next candidate
+// after it is considered.
+func (b *backtracker) pop() {
+	i := b.indices[b.depth]
+	if b.remove != nil {
+		b.remove(i)
+	}
+	b.candidates[b.depth] = i + 1
+}
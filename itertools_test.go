@@ -0,0 +1,547 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+var letters = []string{"a", "b", "c", "d", "e"}
+
+func TestSlicePermutationsRIter(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		r           int
+		expectedLen int
+	}{
+		{"r is negative", letters, -1, 0},
+		{"r = 0", letters, 0, 1},
+		{"r > len(xs)", letters, 6, 0},
+		{"nil slice", nil, 1, 0},
+		{"r = 1", letters, 1, 5},
+		{"r = 3", letters, 3, 60},
+		{"r = len(xs)", letters, 5, 120},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := collect(SlicePermutationsRIter(tt.xs, tt.r))
+			if len(actual) != tt.expectedLen {
+				t.Fatalf("len(actual) = %d; want = %d", len(actual), tt.expectedLen)
+			}
+			assertStrictlySorted(t, actual)
+		})
+	}
+}
+
+func TestSliceCombinationsRIter(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		r           int
+		expectedLen int
+	}{
+		{"r is negative", letters, -1, 0},
+		{"r = 0", letters, 0, 1},
+		{"r > len(xs)", letters, 6, 0},
+		{"nil slice", nil, 1, 0},
+		{"r = 1", letters, 1, 5},
+		{"r = 3", letters, 3, 10},
+		{"r = len(xs)", letters, 5, 1},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := collect(SliceCombinationsRIter(tt.xs, tt.r))
+			if len(actual) != tt.expectedLen {
+				t.Fatalf("len(actual) = %d; want = %d", len(actual), tt.expectedLen)
+			}
+			assertStrictlySorted(t, actual)
+		})
+	}
+}
+
+func TestSliceCombinationsWithReplacementIter(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		r           int
+		expectedLen int
+	}{
+		{"r is negative", letters, -1, 0},
+		{"r = 0", letters, 0, 1},
+		{"r > len(xs)", letters, 6, 210},
+		{"nil slice", nil, 1, 0},
+		{"r = 1", letters, 1, 5},
+		{"r = 3", letters, 3, 35},
+		{"r = len(xs)", letters, 5, 126},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := collect(SliceCombinationsWithReplacementIter(tt.xs, tt.r))
+			if len(actual) != tt.expectedLen {
+				t.Fatalf("len(actual) = %d; want = %d", len(actual), tt.expectedLen)
+			}
+			assertStrictlySorted(t, actual)
+		})
+	}
+}
+
+func TestIteratorEarlyTermination(t *testing.T) {
+	it := SlicePermutationsRIter(letters, 5)
+
+	var first [][]string
+	for it.Next() {
+		first = append(first, it.Value())
+		if len(first) == 3 {
+			break
+		}
+	}
+
+	expected := [][]string{
+		{"a", "b", "c", "d", "e"},
+		{"a", "b", "c", "e", "d"},
+		{"a", "b", "d", "c", "e"},
+	}
+	if !MatrixEqual(first, expected) {
+		t.Errorf("actual = %v; want = %v", first, expected)
+	}
+}
+
+func TestIteratorExhausted(t *testing.T) {
+	it := SliceCombinationsRIter([]string{"a", "b"}, 2)
+	if !it.Next() {
+		t.Fatal("expected a value")
+	}
+	for i := 0; i < 2; i++ {
+		if it.Next() {
+			t.Fatalf("unexpected value %v", it.Value())
+		}
+		if it.Value() != nil {
+			t.Fatalf("actual = %v; want = nil", it.Value())
+		}
+	}
+}
+
+// assertStrictlySorted fails the test unless the tuples are in strictly
+// increasing lexicographic order, which is the order generators produce when
+// given a sorted pool of distinct values.
+func assertStrictlySorted(t *testing.T, xs [][]string) {
+	t.Helper()
+	joined := make([]string, len(xs))
+	for i, x := range xs {
+		joined[i] = strings.Join(x, ",")
+	}
+	if !sort.StringsAreSorted(joined) {
+		t.Fatalf("not sorted: %v", xs)
+	}
+	if !SliceEqual(Dedup(joined), joined) {
+		t.Fatalf("contains duplicates: %v", xs)
+	}
+}
+
+func TestSliceProduct(t *testing.T) {
+	table := []struct {
+		description string
+		pools       [][]string
+		expected    [][]string
+	}{
+		{"no pools", nil, [][]string{{}}},
+		{"one empty pool", [][]string{{"a"}, {}}, nil},
+		{"single pool", [][]string{{"a", "b"}}, [][]string{{"a"}, {"b"}}},
+		{"common case", [][]string{{"dev", "prod"}, {"us", "eu"}, {"small"}}, [][]string{
+			{"dev", "us", "small"},
+			{"dev", "eu", "small"},
+			{"prod", "us", "small"},
+			{"prod", "eu", "small"},
+		}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := SliceProduct(tt.pools...)
+			if !MatrixEqual(actual, tt.expected) {
+				t.Errorf("actual = %v; want = %v", actual, tt.expected)
+			}
+			lazy := collect(SliceProductIter(tt.pools...))
+			if !MatrixEqual(lazy, tt.expected) {
+				t.Errorf("lazy = %v; want = %v", lazy, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSliceProductR(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		r           int
+		expected    [][]string
+	}{
+		{"r is negative", []string{"a", "b"}, -1, nil},
+		{"nil slice", nil, 1, nil},
+		{"empty slice", []string{}, 1, nil},
+		{"two items r = 0", []string{"a", "b"}, 0, [][]string{{}}},
+		{"two items r = 1", []string{"a", "b"}, 1, [][]string{{"a"}, {"b"}}},
+		{"two items r = 2", []string{"a", "b"}, 2, [][]string{{"a", "a"}, {"a", "b"}, {"b", "a"}, {"b", "b"}}},
+		{"r > len(xs)", []string{"a"}, 3, [][]string{{"a", "a", "a"}}},
+		{"duplicates", []string{"a", "a"}, 2, [][]string{{"a", "a"}, {"a", "a"}, {"a", "a"}, {"a", "a"}}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := SliceProductR(tt.xs, tt.r)
+			if !MatrixEqual(actual, tt.expected) {
+				t.Errorf("actual = %v; want = %v", actual, tt.expected)
+			}
+			lazy := collect(SliceProductRIter(tt.xs, tt.r))
+			if !MatrixEqual(lazy, tt.expected) {
+				t.Errorf("lazy = %v; want = %v", lazy, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSlicePowersetRange(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		min, max    int
+		expected    [][]string
+	}{
+		{"nil slice", nil, 0, 0, [][]string{{}}},
+		{"min > max", []string{"a", "b"}, 2, 1, nil},
+		{"min > len(xs)", []string{"a", "b"}, 3, 3, nil},
+		{"full range", []string{"a", "b", "c"}, 0, 3, [][]string{
+			{},
+			{"a"}, {"b"}, {"c"},
+			{"a", "b"}, {"a", "c"}, {"b", "c"},
+			{"a", "b", "c"},
+		}},
+		{"out of bounds clamped", []string{"a", "b"}, -5, 5, [][]string{
+			{}, {"a"}, {"b"}, {"a", "b"},
+		}},
+		{"bounded", []string{"a", "b", "c"}, 1, 2, [][]string{
+			{"a"}, {"b"}, {"c"},
+			{"a", "b"}, {"a", "c"}, {"b", "c"},
+		}},
+		{"duplicates", []string{"a", "a"}, 1, 1, [][]string{{"a"}, {"a"}}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := SlicePowersetRange(tt.xs, tt.min, tt.max)
+			if !MatrixEqual(actual, tt.expected) {
+				t.Errorf("actual = %v; want = %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSlicePowerset(t *testing.T) {
+	actual := SlicePowerset(letters)
+	if len(actual) != 1<<uint(len(letters)) {
+		t.Fatalf("len(actual) = %d; want = %d", len(actual), 1<<uint(len(letters)))
+	}
+	if actual[0] == nil || len(actual[0]) != 0 {
+		t.Errorf("actual[0] = %#v; want = []string{}", actual[0])
+	}
+	if !SliceEqual(actual[len(actual)-1], letters) {
+		t.Errorf("actual[%d] = %v; want = %v", len(actual)-1, actual[len(actual)-1], letters)
+	}
+}
+
+func TestSliceMultisetPermutationsR(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		r           int
+		expected    [][]string
+	}{
+		{"r is negative", []string{"a", "b"}, -1, nil},
+		{"r > len(xs)", []string{"a", "b"}, 3, nil},
+		{"nil slice", nil, 1, nil},
+		{"two items r = 0", []string{"a", "b"}, 0, [][]string{{}}},
+		{"distinct values", []string{"a", "b"}, 2, [][]string{{"a", "b"}, {"b", "a"}}},
+		{"duplicates", []string{"a", "a"}, 2, [][]string{{"a", "a"}}},
+		{"common case", []string{"a", "b", "a"}, 2, [][]string{{"a", "a"}, {"a", "b"}, {"b", "a"}}},
+		{"full length", []string{"b", "a", "b"}, 3, [][]string{{"b", "b", "a"}, {"b", "a", "b"}, {"a", "b", "b"}}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := SliceMultisetPermutationsR(tt.xs, tt.r)
+			if !MatrixEqual(actual, tt.expected) {
+				t.Errorf("actual = %v; want = %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSliceMultisetCombinationsR(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		r           int
+		expected    [][]string
+	}{
+		{"r is negative", []string{"a", "b"}, -1, nil},
+		{"r > len(xs)", []string{"a", "b"}, 3, nil},
+		{"nil slice", nil, 1, nil},
+		{"two items r = 0", []string{"a", "b"}, 0, [][]string{{}}},
+		{"distinct values", []string{"a", "b", "c"}, 2, [][]string{{"a", "b"}, {"a", "c"}, {"b", "c"}}},
+		{"duplicates", []string{"a", "a"}, 2, [][]string{{"a", "a"}}},
+		{"common case", []string{"a", "b", "a", "c"}, 2, [][]string{
+			{"a", "a"}, {"a", "b"}, {"a", "c"}, {"b", "c"},
+		}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := SliceMultisetCombinationsR(tt.xs, tt.r)
+			if !MatrixEqual(actual, tt.expected) {
+				t.Errorf("actual = %v; want = %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSliceMultisetMatchesDedup(t *testing.T) {
+	pool := []string{"a", "a", "b", "b", "b", "c"}
+	table := []struct {
+		description string
+		multiset    func([]string, int) [][]string
+		positional  func([]string, int) [][]string
+	}{
+		{"permutations", SliceMultisetPermutationsR, SlicePermutationsR},
+		{"combinations", SliceMultisetCombinationsR, SliceCombinationsR},
+	}
+
+	joined := func(xs [][]string) []string {
+		ys := make([]string, len(xs))
+		for i, x := range xs {
+			ys[i] = strings.Join(x, ",")
+		}
+		sort.Strings(ys)
+		return ys
+	}
+
+	for _, tt := range table {
+		for r := 1; r <= len(pool); r++ {
+			actual := tt.multiset(pool, r)
+			assertStrictlySorted(t, actual)
+			if expected := Dedup(joined(tt.positional(pool, r))); !SliceEqual(joined(actual), expected) {
+				t.Errorf("%s r = %d: actual = %v; want = %v", tt.description, r, actual, expected)
+			}
+		}
+	}
+}
+
+// TestItertoolsParity checks edge cases against the output of the equivalent
+// call to Python's itertools, which is used as the description.
+func TestItertoolsParity(t *testing.T) {
+	table := []struct {
+		description string
+		actual      [][]string
+		expected    [][]string
+	}{
+		{"permutations('', 0)", SlicePermutationsR(nil, 0), [][]string{{}}},
+		{"permutations('', 1)", SlicePermutationsR(nil, 1), nil},
+		{"permutations('')", SlicePermutations([]string{}), [][]string{{}}},
+		{"permutations('ab', 0)", SlicePermutationsR([]string{"a", "b"}, 0), [][]string{{}}},
+		{"permutations('ab', 3)", SlicePermutationsR([]string{"a", "b"}, 3), nil},
+		{"combinations('', 0)", SliceCombinationsR(nil, 0), [][]string{{}}},
+		{"combinations('', 1)", SliceCombinationsR([]string{}, 1), nil},
+		{"combinations('ab', 0)", SliceCombinationsR([]string{"a", "b"}, 0), [][]string{{}}},
+		{"combinations('ab', 3)", SliceCombinationsR([]string{"a", "b"}, 3), nil},
+		{"combinations_with_replacement('', 0)", SliceCombinationsWithReplacement(nil, 0), [][]string{{}}},
+		{"combinations_with_replacement('', 1)", SliceCombinationsWithReplacement([]string{}, 1), nil},
+		{"combinations_with_replacement('ab', 0)", SliceCombinationsWithReplacement([]string{"a", "b"}, 0), [][]string{{}}},
+		{"combinations_with_replacement('a', 3)", SliceCombinationsWithReplacement([]string{"a"}, 3), [][]string{{"a", "a", "a"}}},
+		{"combinations_with_replacement('ab', 3)", SliceCombinationsWithReplacement([]string{"a", "b"}, 3), [][]string{
+			{"a", "a", "a"}, {"a", "a", "b"}, {"a", "b", "b"}, {"b", "b", "b"},
+		}},
+		{"product()", SliceProduct(), [][]string{{}}},
+		{"product('ab', '')", SliceProduct([]string{"a", "b"}, nil), nil},
+		{"product('', repeat=0)", SliceProductR(nil, 0), [][]string{{}}},
+		{"product('', repeat=1)", SliceProductR(nil, 1), nil},
+		{"product('ab', repeat=0)", SliceProductR([]string{"a", "b"}, 0), [][]string{{}}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			if !MatrixEqual(tt.actual, tt.expected) {
+				t.Errorf("actual = %v; want = %v", tt.actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSliceVisit(t *testing.T) {
+	table := []struct {
+		description string
+		visit       func([]string, int, []string, func([]string) bool)
+		generate    func([]string, int) [][]string
+	}{
+		{"permutations", SlicePermutationsRVisit, SlicePermutationsR},
+		{"combinations", SliceCombinationsRVisit, SliceCombinationsR},
+		{"combinations with replacement", SliceCombinationsWithReplacementVisit, SliceCombinationsWithReplacement},
+	}
+
+	for _, tt := range table {
+		for r := -1; r <= len(letters)+1; r++ {
+			var actual [][]string
+			tt.visit(letters, r, nil, func(tuple []string) bool {
+				actual = append(actual, append([]string{}, tuple...))
+				return true
+			})
+			if expected := tt.generate(letters, r); !MatrixEqual(actual, expected) {
+				t.Errorf("%s r = %d: actual = %v; want = %v", tt.description, r, actual, expected)
+			}
+		}
+
+		t.Run(tt.description+" stops early", func(t *testing.T) {
+			calls := 0
+			tt.visit(letters, 2, nil, func([]string) bool {
+				calls++
+				return calls < 3
+			})
+			if calls != 3 {
+				t.Errorf("calls = %d; want = 3", calls)
+			}
+		})
+
+		t.Run(tt.description+" allocates nothing per tuple", func(t *testing.T) {
+			buf := make([]string, 3)
+			allocs := func(pool []string) float64 {
+				return testing.AllocsPerRun(10, func() {
+					tt.visit(pool, 3, buf, func([]string) bool { return true })
+				})
+			}
+
+			small, large := allocs(letters[:3]), allocs(strings.Split("abcdefghijkl", ""))
+			if small != large {
+				t.Errorf("allocs = %v for a small pool and %v for a large pool", small, large)
+			}
+		})
+	}
+}
+
+func BenchmarkSlicePermutationsR(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		SlicePermutationsR(letters, 3)
+	}
+}
+
+func BenchmarkSlicePermutationsRVisit(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]string, 3)
+	for i := 0; i < b.N; i++ {
+		SlicePermutationsRVisit(letters, 3, buf, func([]string) bool { return true })
+	}
+}
+
+func BenchmarkSliceCombinationsR(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		SliceCombinationsR(letters, 3)
+	}
+}
+
+func BenchmarkSliceCombinationsRVisit(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]string, 3)
+	for i := 0; i < b.N; i++ {
+		SliceCombinationsRVisit(letters, 3, buf, func([]string) bool { return true })
+	}
+}
+
+func BenchmarkSliceCombinationsWithReplacementVisit(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]string, 3)
+	for i := 0; i < b.N; i++ {
+		SliceCombinationsWithReplacementVisit(letters, 3, buf, func([]string) bool { return true })
+	}
+}
+
+func TestSlicePruned(t *testing.T) {
+	// Both constraints are checked on every prefix, so filtering the full
+	// output with them must agree with pruning.
+	noAdjacentVowels := func(prefix []string) bool {
+		for i := 1; i < len(prefix); i++ {
+			if strings.Contains("ae", prefix[i-1]) && strings.Contains("ae", prefix[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	dNeverFollowsC := func(prefix []string) bool {
+		seenC := false
+		for _, x := range prefix {
+			seenC = seenC || x == "c"
+			if x == "d" && seenC {
+				return false
+			}
+		}
+		return true
+	}
+
+	table := []struct {
+		description string
+		pruned      func([]string, int, func([]string) bool) [][]string
+		generate    func([]string, int) [][]string
+	}{
+		{"permutations", SlicePermutationsRPruned, SlicePermutationsR},
+		{"combinations", SliceCombinationsRPruned, SliceCombinationsR},
+		{"combinations with replacement", SliceCombinationsWithReplacementPruned, SliceCombinationsWithReplacement},
+	}
+
+	for _, tt := range table {
+		for _, valid := range []func([]string) bool{noAdjacentVowels, dNeverFollowsC} {
+			for r := -1; r <= len(letters)+1; r++ {
+				var expected [][]string
+				for _, x := range tt.generate(letters, r) {
+					if valid(x) {
+						expected = append(expected, x)
+					}
+				}
+				if actual := tt.pruned(letters, r, valid); !MatrixEqual(actual, expected) {
+					t.Errorf("%s r = %d: actual = %v; want = %v", tt.description, r, actual, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestSlicePermutationsRPrunedPrunes(t *testing.T) {
+	calls := 0
+	actual := SlicePermutationsRPruned(letters, 5, func(prefix []string) bool {
+		calls++
+		return prefix[0] == "e"
+	})
+	if len(actual) != 24 {
+		t.Fatalf("len(actual) = %d; want = 24", len(actual))
+	}
+
+	// 5 calls for the first position, then every call is beneath "e".
+	if expected := 5 + 4 + 4*3 + 4*3*2 + 4*3*2; calls != expected {
+		t.Errorf("calls = %d; want = %d", calls, expected)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// ErrTooManyResults is matched by every *TooManyResultsError.
+var ErrTooManyResults = errors.New("the result exceeds the configured limits")
+
+// Limits bounds the size of the result of a combinatorics generator.
+//
+// A zero value for either field means that it is not enforced.
+type Limits struct {
+	// MaxResults is the maximum number of tuples.
+	MaxResults uint64
+
+	// MaxBytes is the maximum number of bytes of slices that would be
+	// allocated to hold the tuples. Strings shared with the pool are not
+	// counted, but the bytes of strings built for the result, such as by
+	// ExpandPatternLimited and ExpandBracesLimited, are.
+	MaxBytes uint64
+}
+
+// TooManyResultsError is returned when the precomputed size of a result
+// exceeds its Limits. No tuples are generated when it is returned.
+type TooManyResultsError struct {
+	Count  *big.Int
+	Bytes  *big.Int
+	Limits Limits
+}
+
+func (e *TooManyResultsError) Error() string {
+	return fmt.Sprintf("%s: %s results requiring an estimated %s bytes", ErrTooManyResults, e.Count, e.Bytes)
+}
+
+// Is reports whether target is ErrTooManyResults.
+func (e *TooManyResultsError) Is(target error) bool { return target == ErrTooManyResults }
+
+// SlicePermutationsLimited is SlicePermutations, but returns a
+// *TooManyResultsError instead if the result would exceed the provided
+// Limits.
+func SlicePermutationsLimited(pool []string, limits Limits) ([][]string, error) {
+	return SlicePermutationsRLimited(pool, len(pool), limits)
+}
+
+// SlicePermutationsRLimited is SlicePermutationsR, but returns a
+// *TooManyResultsError instead if the result would exceed the provided
+// Limits.
+func SlicePermutationsRLimited(pool []string, r int, limits Limits) ([][]string, error) {
+	if err := limits.check(CountPermutationsBig(len(pool), r), r); err != nil {
+		return nil, err
+	}
+	return SlicePermutationsR(pool, r), nil
+}
+
+// SliceCombinationsRLimited is SliceCombinationsR, but returns a
+// *TooManyResultsError instead if the result would exceed the provided
+// Limits.
+func SliceCombinationsRLimited(pool []string, r int, limits Limits) ([][]string, error) {
+	if err := limits.check(CountCombinationsBig(len(pool), r), r); err != nil {
+		return nil, err
+	}
+	return SliceCombinationsR(pool, r), nil
+}
+
+// SliceCombinationsWithReplacementLimited is
+// SliceCombinationsWithReplacement, but returns a *TooManyResultsError
+// instead if the result would exceed the provided Limits.
+func SliceCombinationsWithReplacementLimited(pool []string, r int, limits Limits) ([][]string, error) {
+	if err := limits.check(CountCombinationsWithReplacementBig(len(pool), r), r); err != nil {
+		return nil, err
+	}
+	return SliceCombinationsWithReplacement(pool, r), nil
+}
+
+// SliceProductLimited is SliceProduct, but returns a *TooManyResultsError
+// instead if the result would exceed the provided Limits.
+func SliceProductLimited(limits Limits, pools ...[]string) ([][]string, error) {
+	lens := make([]int, len(pools))
+	for i, pool := range pools {
+		lens[i] = len(pool)
+	}
+	if err := limits.check(CountProductBig(lens...), len(pools)); err != nil {
+		return nil, err
+	}
+	return SliceProduct(pools...), nil
+}
+
+// SliceProductRLimited is SliceProductR, but returns a *TooManyResultsError
+// instead if the result would exceed the provided Limits.
+func SliceProductRLimited(pool []string, r int, limits Limits) ([][]string, error) {
+	if err := limits.check(CountProductRBig(len(pool), r), r); err != nil {
+		return nil, err
+	}
+	return SliceProductR(pool, r), nil
+}
+
+// SlicePowersetRangeLimited is SlicePowersetRange, but returns a
+// *TooManyResultsError instead if the result would exceed the provided
+// Limits.
+func SlicePowersetRangeLimited(pool []string, min, max int, limits Limits) ([][]string, error) {
+	n := len(pool)
+	min, max = maxInt(min, 0), minInt(max, n)
+	count := CountPowersetRangeBig(n, min, max)
+
+	// Every r-length subset holds r strings, and r*C(n, r) = n*C(n-1, r-1).
+	elements := CountPowersetRangeBig(n-1, min-1, max-1)
+	elements.Mul(elements, big.NewInt(int64(n)))
+
+	bytes := new(big.Int).Mul(count, big.NewInt(sliceHeaderSize))
+	bytes.Add(bytes, elements.Mul(elements, big.NewInt(stringHeaderSize)))
+	if err := limits.checkBytes(count, bytes); err != nil {
+		return nil, err
+	}
+	return SlicePowersetRange(pool, min, max), nil
+}
+
+// The sizes of the headers of a []string and a string, which are three and two
+// words respectively.
+var (
+	sliceHeaderSize  = int64(reflect.TypeOf([]string(nil)).Size())
+	stringHeaderSize = int64(reflect.TypeOf("").Size())
+)
+
+// estimateBytes returns the number of bytes of slices needed to hold count
+// tuples of length r.
+func estimateBytes(count *big.Int, r int) *big.Int {
+	perTuple := big.NewInt(sliceHeaderSize + int64(r)*stringHeaderSize)
+	return perTuple.Mul(perTuple, count)
+}
+
+func (l Limits) check(count *big.Int, r int) error {
+	return l.checkBytes(count, estimateBytes(count, r))
+}
+
+func (l Limits) checkBytes(count, bytes *big.Int) error {
+	exceeds := func(x *big.Int, limit uint64) bool {
+		return limit != 0 && x.Cmp(new(big.Int).SetUint64(limit)) > 0
+	}
+	if exceeds(count, l.MaxResults) || exceeds(bytes, l.MaxBytes) {
+		return &TooManyResultsError{Count: count, Bytes: bytes, Limits: l}
+	}
+	return nil
+}
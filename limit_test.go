@@ -0,0 +1,111 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+func TestLimited(t *testing.T) {
+	table := []struct {
+		description string
+		limited     func(Limits) ([][]string, error)
+		expectedLen int64
+	}{
+		{"permutations", func(l Limits) ([][]string, error) { return SlicePermutationsLimited(letters, l) }, 120},
+		{"permutations r", func(l Limits) ([][]string, error) { return SlicePermutationsRLimited(letters, 2, l) }, 20},
+		{"combinations", func(l Limits) ([][]string, error) { return SliceCombinationsRLimited(letters, 2, l) }, 10},
+		{"combinations with replacement", func(l Limits) ([][]string, error) { return SliceCombinationsWithReplacementLimited(letters, 2, l) }, 15},
+		{"product", func(l Limits) ([][]string, error) { return SliceProductLimited(l, letters, letters[:2]) }, 10},
+		{"product r", func(l Limits) ([][]string, error) { return SliceProductRLimited(letters, 2, l) }, 25},
+		{"powerset", func(l Limits) ([][]string, error) { return SlicePowersetRangeLimited(letters, 0, 5, l) }, 32},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual, err := tt.limited(Limits{})
+			if err != nil {
+				t.Fatalf("unlimited err = %s", err)
+			}
+			if int64(len(actual)) != tt.expectedLen {
+				t.Fatalf("len(actual) = %d; want = %d", len(actual), tt.expectedLen)
+			}
+
+			if _, err := tt.limited(Limits{MaxResults: uint64(tt.expectedLen)}); err != nil {
+				t.Errorf("at MaxResults err = %s", err)
+			}
+
+			_, err = tt.limited(Limits{MaxResults: uint64(tt.expectedLen - 1)})
+			var tooMany *TooManyResultsError
+			if !errors.As(err, &tooMany) {
+				t.Fatalf("err = %v; want = *TooManyResultsError", err)
+			}
+			if !errors.Is(err, ErrTooManyResults) {
+				t.Errorf("errors.Is(%v, ErrTooManyResults) = false", err)
+			}
+			if tooMany.Count.Int64() != tt.expectedLen {
+				t.Errorf("Count = %s; want = %d", tooMany.Count, tt.expectedLen)
+			}
+		})
+	}
+}
+
+func TestLimitedMaxBytes(t *testing.T) {
+	pool := make([]string, 15)
+	for i := range pool {
+		pool[i] = strconv.Itoa(i)
+	}
+
+	// The 15! permutations would need terabytes.
+	_, err := SlicePermutationsLimited(pool, Limits{MaxBytes: 1 << 30})
+	var tooMany *TooManyResultsError
+	if !errors.As(err, &tooMany) {
+		t.Fatalf("err = %v; want = *TooManyResultsError", err)
+	}
+
+	expected := estimateBytes(CountPermutationsBig(15, 15), 15)
+	if tooMany.Bytes.Cmp(expected) != 0 {
+		t.Errorf("Bytes = %s; want = %s", tooMany.Bytes, expected)
+	}
+
+	if _, err := SliceCombinationsRLimited(pool, 2, Limits{MaxBytes: 1 << 30}); err != nil {
+		t.Errorf("err = %s", err)
+	}
+
+	for _, bounds := range [][2]int{{0, 15}, {-3, 4}, {2, 99}, {5, 3}, {math.MinInt, math.MaxInt}, {math.MinInt, 3}} {
+		expected := new(big.Int)
+		for r := maxInt(bounds[0], 0); r <= bounds[1] && r <= len(pool); r++ {
+			expected.Add(expected, estimateBytes(CountCombinationsBig(len(pool), r), r))
+		}
+
+		_, err := SlicePowersetRangeLimited(pool, bounds[0], bounds[1], Limits{MaxBytes: 1})
+		if expected.Sign() == 0 {
+			if err != nil {
+				t.Errorf("powerset %v err = %s", bounds, err)
+			}
+			continue
+		}
+		if !errors.As(err, &tooMany) {
+			t.Fatalf("powerset %v err = %v; want = *TooManyResultsError", bounds, err)
+		}
+		if tooMany.Bytes.Cmp(expected) != 0 {
+			t.Errorf("powerset %v Bytes = %s; want = %s", bounds, tooMany.Bytes, expected)
+		}
+	}
+}
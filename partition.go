@@ -0,0 +1,249 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import "math/big"
+
+// PartitionIterator lazily produces a sequence of partitions of a string
+// slice.
+//
+// It is used the same way as an Iterator.
+type PartitionIterator struct {
+	next  func() ([][]string, bool)
+	value [][]string
+}
+
+// Next advances the PartitionIterator to its next value. It returns false
+// once the sequence has been exhausted.
+func (it *PartitionIterator) Next() bool {
+	if it.next == nil {
+		return false
+	}
+
+	v, ok := it.next()
+	if !ok {
+		it.next, it.value = nil, nil
+		return false
+	}
+	it.value = v
+	return true
+}
+
+// Value returns the value produced by the most recent call to Next.
+//
+// Every value is newly allocated, so it is safe to retain.
+func (it *PartitionIterator) Value() [][]string { return it.value }
+
+// SlicePartitions returns every set partition of the provided string slice:
+// every way to split it into non-empty, unordered groups.
+//
+// Within each partition, the elements of a group keep the order of the input
+// iterable and the groups are ordered by their first element. The partitions
+// are emitted in lexicographic ordering of the group that each element of the
+// input iterable is assigned to. An empty pool has a single partition with no
+// groups.
+//
+// Elements are treated as unique based on their position, not on their value.
+// So if the input elements are unique, there will be no repeat partitions.
+func SlicePartitions(pool []string) [][][]string {
+	return collectPartitions(SlicePartitionsIter(pool))
+}
+
+// SlicePartitionsIter is the lazy form of SlicePartitions.
+func SlicePartitionsIter(pool []string) *PartitionIterator {
+	return partitionsIter(pool, -1)
+}
+
+// SlicePartitionsK returns every way to split the provided string slice into
+// exactly k non-empty, unordered groups.
+//
+// If k is less than 0 or larger than the length of the pool, nil is returned.
+//
+// The partitions are the subset of those produced by SlicePartitions that
+// have k groups, emitted in the same order.
+func SlicePartitionsK(pool []string, k int) [][][]string {
+	return collectPartitions(SlicePartitionsKIter(pool, k))
+}
+
+// SlicePartitionsKIter is the lazy form of SlicePartitionsK.
+func SlicePartitionsKIter(pool []string, k int) *PartitionIterator {
+	if k < 0 || k > len(pool) || (k == 0 && len(pool) > 0) {
+		return &PartitionIterator{}
+	}
+	return partitionsIter(pool, k)
+}
+
+// CountPartitions returns the number of partitions that SlicePartitions
+// produces for a pool of length n, which is the nth Bell number.
+//
+// Returns ErrOverflow if the count does not fit into a uint64.
+func CountPartitions(n int) (uint64, error) {
+	return toUint64(CountPartitionsBig(n))
+}
+
+// CountPartitionsBig is CountPartitions for counts that do not fit into a
+// uint64.
+func CountPartitionsBig(n int) *big.Int {
+	count := new(big.Int)
+	if n < 0 {
+		return count
+	}
+	for _, s := range stirlingRow(n) {
+		count.Add(count, s)
+	}
+	return count
+}
+
+// CountPartitionsK returns the number of partitions that SlicePartitionsK
+// produces for a pool of length n, which is the Stirling number of the second
+// kind S(n, k).
+//
+// Returns ErrOverflow if the count does not fit into a uint64.
+func CountPartitionsK(n, k int) (uint64, error) {
+	return toUint64(CountPartitionsKBig(n, k))
+}
+
+// CountPartitionsKBig is CountPartitionsK for counts that do not fit into a
+// uint64.
+func CountPartitionsKBig(n, k int) *big.Int {
+	if n < 0 || k < 0 || k > n {
+		return new(big.Int)
+	}
+	return stirlingRow(n)[k]
+}
+
+// stirlingRow returns the Stirling numbers of the second kind S(n, k) for
+// every k from 0 to n.
+func stirlingRow(n int) []*big.Int {
+	row := []*big.Int{big.NewInt(1)}
+	for i := 1; i <= n; i++ {
+		next := make([]*big.Int, i+1)
+		next[0] = new(big.Int)
+		for k := 1; k <= i; k++ {
+			// S(i, k) = k*S(i-1, k) + S(i-1, k-1)
+			s := new(big.Int)
+			if k < i {
+				s.Mul(big.NewInt(int64(k)), row[k])
+			}
+			next[k] = s.Add(s, row[k-1])
+		}
+		row = next
+	}
+	return row
+}
+
+func collectPartitions(it *PartitionIterator) [][][]string {
+	var ys [][][]string
+	for it.Next() {
+		ys = append(ys, it.Value())
+	}
+	return ys
+}
+
+// partitionsIter walks the restricted growth strings of pool in
+// lexicographic order, where each element is assigned the index of its group
+// and no group is used before every lower group has been. If k is not
+// negative, only the strings that use exactly k groups are walked.
+func partitionsIter(pool []string, k int) *PartitionIterator {
+	rgs := newRestrictedGrowth(len(pool), k)
+	started := false
+	return &PartitionIterator{next: func() ([][]string, bool) {
+		if started && !rgs.next() {
+			return nil, false
+		}
+		started = true
+
+		groups := make([][]string, 0, len(pool))
+		for i, g := range rgs.groups {
+			if g == len(groups) {
+				groups = append(groups, nil)
+			}
+			groups[g] = append(groups[g], pool[i])
+		}
+		return groups, true
+	}}
+}
+
+// restrictedGrowth is a restricted growth string along with the running
+// maximum of each of its prefixes.
+type restrictedGrowth struct {
+	groups []int
+	maxes  []int
+	k      int
+}
+
+func newRestrictedGrowth(n, k int) *restrictedGrowth {
+	rg := &restrictedGrowth{groups: make([]int, n), maxes: make([]int, n), k: k}
+	if n > 0 {
+		rg.fill(1)
+	}
+	return rg
+}
+
+// fill assigns the smallest groups that can still reach k groups to every
+// position from i onward.
+func (rg *restrictedGrowth) fill(i int) {
+	n := len(rg.groups)
+	for ; i < n; i++ {
+		prev := rg.maxes[i-1]
+		g := 0
+		if rg.k >= 0 && n-i <= rg.k-1-prev {
+			// Every remaining position must open a new group.
+			g = prev + 1
+		}
+		rg.groups[i] = g
+		rg.maxes[i] = maxInt(prev, g)
+	}
+}
+
+// next advances to the lexicographically next restricted growth string. It
+// returns false when there is none.
+func (rg *restrictedGrowth) next() bool {
+	n := len(rg.groups)
+	for i := n - 1; i > 0; i-- {
+		prev := rg.maxes[i-1]
+		limit := prev + 1
+		if rg.k >= 0 && limit > rg.k-1 {
+			limit = rg.k - 1
+		}
+
+		for g := rg.groups[i] + 1; g <= limit; g++ {
+			max := maxInt(prev, g)
+			if rg.k >= 0 && max+(n-1-i) < rg.k-1 {
+				// Too few positions remain to open the missing groups.
+				continue
+			}
+			rg.groups[i] = g
+			rg.maxes[i] = max
+			rg.fill(i + 1)
+			return true
+		}
+	}
+	return false
+}
+
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+func minInt(x, y int) int {
+	if x < y {
+		return x
+	}
+	return y
+}
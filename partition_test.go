@@ -0,0 +1,147 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"fmt"
+	"testing"
+)
+
+func partitionsEqual(xs, ys [][][]string) bool {
+	if len(xs) != len(ys) {
+		return false
+	}
+	for i, x := range xs {
+		if !MatrixEqual(x, ys[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSlicePartitions(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		expected    [][][]string
+	}{
+		{"nil slice", nil, [][][]string{{}}},
+		{"single item", []string{"a"}, [][][]string{{{"a"}}}},
+		{"two items", []string{"a", "b"}, [][][]string{
+			{{"a", "b"}},
+			{{"a"}, {"b"}},
+		}},
+		{"common case", []string{"a", "b", "c"}, [][][]string{
+			{{"a", "b", "c"}},
+			{{"a", "b"}, {"c"}},
+			{{"a", "c"}, {"b"}},
+			{{"a"}, {"b", "c"}},
+			{{"a"}, {"b"}, {"c"}},
+		}},
+		{"duplicates", []string{"a", "a"}, [][][]string{
+			{{"a", "a"}},
+			{{"a"}, {"a"}},
+		}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := SlicePartitions(tt.xs)
+			if !partitionsEqual(actual, tt.expected) {
+				t.Errorf("actual = %v; want = %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSlicePartitionsK(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		k           int
+		expected    [][][]string
+	}{
+		{"k is negative", []string{"a"}, -1, nil},
+		{"k > len(xs)", []string{"a"}, 2, nil},
+		{"k = 0", []string{"a"}, 0, nil},
+		{"nil slice k = 0", nil, 0, [][][]string{{}}},
+		{"k = 1", []string{"a", "b", "c"}, 1, [][][]string{{{"a", "b", "c"}}}},
+		{"k = 2", []string{"a", "b", "c"}, 2, [][][]string{
+			{{"a", "b"}, {"c"}},
+			{{"a", "c"}, {"b"}},
+			{{"a"}, {"b", "c"}},
+		}},
+		{"k = len(xs)", []string{"a", "b", "c"}, 3, [][][]string{{{"a"}, {"b"}, {"c"}}}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := SlicePartitionsK(tt.xs, tt.k)
+			if !partitionsEqual(actual, tt.expected) {
+				t.Errorf("actual = %v; want = %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSlicePartitionsKMatchesSlicePartitions(t *testing.T) {
+	for n := 0; n <= 7; n++ {
+		pool := make([]string, n)
+		for i := range pool {
+			pool[i] = fmt.Sprint(i)
+		}
+		all := SlicePartitions(pool)
+
+		for k := 0; k <= n; k++ {
+			var expected [][][]string
+			for _, p := range all {
+				if len(p) == k {
+					expected = append(expected, p)
+				}
+			}
+			if actual := SlicePartitionsK(pool, k); !partitionsEqual(actual, expected) {
+				t.Errorf("n = %d k = %d: actual = %v; want = %v", n, k, actual, expected)
+			}
+		}
+	}
+}
+
+func TestCountPartitions(t *testing.T) {
+	bell := []uint64{1, 1, 2, 5, 15, 52, 203, 877}
+	for n, expected := range bell {
+		pool := make([]string, n)
+		for i := range pool {
+			pool[i] = fmt.Sprint(i)
+		}
+
+		if generated := uint64(len(SlicePartitions(pool))); generated != expected {
+			t.Errorf("len(SlicePartitions) n = %d: actual = %d; want = %d", n, generated, expected)
+		}
+		if actual, err := CountPartitions(n); actual != expected || err != nil {
+			t.Errorf("CountPartitions(%d) = %d, %v; want = %d", n, actual, err, expected)
+		}
+
+		for k := -1; k <= n+1; k++ {
+			generated := uint64(len(SlicePartitionsK(pool, k)))
+			if actual, err := CountPartitionsK(n, k); actual != generated || err != nil {
+				t.Errorf("CountPartitionsK(%d, %d) = %d, %v; want = %d", n, k, actual, err, generated)
+			}
+		}
+	}
+
+	if _, err := CountPartitions(30); err != ErrOverflow {
+		t.Errorf("err = %v; want = %v", err, ErrOverflow)
+	}
+}
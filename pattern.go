@@ -0,0 +1,346 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PatternError describes why a pattern could not be parsed.
+type PatternError struct {
+	Pattern string
+	Offset  int
+	Msg     string
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("invalid pattern %q at byte offset %d: %s", e.Pattern, e.Offset, e.Msg)
+}
+
+// Pattern is a parsed pattern that matches a finite set of strings, each of
+// which is one choice of character from every position of the pattern.
+//
+// Patterns use a restricted regular expression syntax combined with mask
+// tokens:
+//
+//	x       the literal character x
+//	\x      the literal character x, even if it is special
+//	\d      a digit, 0-9
+//	[a-cx]  one of the characters in the class, which may contain ranges
+//	{n}     the preceding position repeated exactly n times
+//	?l      a lowercase letter, a-z
+//	?u      an uppercase letter, A-Z
+//	?d      a digit, 0-9
+//	?h      a lowercase hexadecimal digit, 0-9a-f
+//	?H      an uppercase hexadecimal digit, 0-9A-F
+//	?s      a printable ASCII symbol or space
+//	?a      any of ?l, ?u, ?d, or ?s
+//	??      the literal character ?
+//
+// The characters ( ) | * + . ^ $ and ] are reserved and must be escaped to
+// be used literally. A repetition must follow a position, not another
+// repetition.
+type Pattern struct {
+	pools [][]string
+}
+
+// ParsePattern parses a Pattern.
+//
+// Returns a *PatternError if the pattern is malformed or uses unsupported
+// syntax.
+func ParsePattern(pattern string) (*Pattern, error) {
+	p := &patternParser{pattern: pattern}
+	pools, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{pools: pools}, nil
+}
+
+// ExpandPattern returns every string matched by the provided pattern, in the
+// order produced by Pattern.Iter. The result is not bounded, so untrusted
+// patterns should be expanded with ExpandPatternLimited or Pattern.Iter.
+func ExpandPattern(pattern string) ([]string, error) {
+	p, err := ParsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return p.Expand(), nil
+}
+
+// ExpandPatternLimited is ExpandPattern, but returns a *TooManyResultsError
+// instead if the result would exceed the provided Limits.
+func ExpandPatternLimited(pattern string, limits Limits) ([]string, error) {
+	p, err := ParsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return p.ExpandLimited(limits)
+}
+
+// Expand returns every string matched by the Pattern, in the order produced
+// by Iter.
+func (p *Pattern) Expand() []string {
+	var xs []string
+	for it := p.Iter(); it.Next(); {
+		xs = append(xs, it.Value())
+	}
+	return xs
+}
+
+// ExpandLimited is Expand, but returns a *TooManyResultsError instead if the
+// result would exceed the provided Limits.
+func (p *Pattern) ExpandLimited(limits Limits) ([]string, error) {
+	count := p.CountBig()
+	bytes := new(big.Int).Mul(count, big.NewInt(stringHeaderSize))
+	for _, pool := range p.pools {
+		// Each character in the pool appears in count/len(pool) strings.
+		chars := 0
+		for _, c := range pool {
+			chars += len(c)
+		}
+		n := new(big.Int).Quo(count, big.NewInt(int64(len(pool))))
+		bytes.Add(bytes, n.Mul(n, big.NewInt(int64(chars))))
+	}
+	if err := limits.checkBytes(count, bytes); err != nil {
+		return nil, err
+	}
+	return p.Expand(), nil
+}
+
+// Iter returns a PatternIterator over every string matched by the Pattern.
+//
+// The strings are the Cartesian product of the characters allowed at each
+// position, emitted in the order SliceProduct produces them: characters in
+// a class are ordered as they are written and the last position varies
+// fastest.
+func (p *Pattern) Iter() *PatternIterator {
+	return &PatternIterator{it: SliceProductIter(p.pools...)}
+}
+
+// Count returns the number of strings matched by the Pattern.
+//
+// Returns ErrOverflow if the count does not fit into a uint64.
+func (p *Pattern) Count() (uint64, error) {
+	return toUint64(p.CountBig())
+}
+
+// CountBig is Count for counts that do not fit into a uint64.
+func (p *Pattern) CountBig() *big.Int {
+	lens := make([]int, len(p.pools))
+	for i, pool := range p.pools {
+		lens[i] = len(pool)
+	}
+	return CountProductBig(lens...)
+}
+
+// PatternIterator lazily produces the strings matched by a Pattern.
+//
+// It is used the same way as an Iterator.
+type PatternIterator struct {
+	it    *Iterator
+	value string
+}
+
+// Next advances the PatternIterator to its next string. It returns false once
+// every string has been produced.
+func (it *PatternIterator) Next() bool {
+	if !it.it.Next() {
+		it.value = ""
+		return false
+	}
+	it.value = strings.Join(it.it.Value(), "")
+	return true
+}
+
+// Value returns the string produced by the most recent call to Next.
+func (it *PatternIterator) Value() string { return it.value }
+
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = " !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+var maskClasses = map[rune]string{
+	'l': lowerChars,
+	'u': upperChars,
+	'd': digitChars,
+	'h': digitChars + "abcdef",
+	'H': digitChars + "ABCDEF",
+	's': symbolChars,
+	'a': lowerChars + upperChars + digitChars + symbolChars,
+}
+
+// maxPatternRepeat bounds repetition counts so that a short pattern cannot
+// exhaust memory while being parsed.
+const maxPatternRepeat = 1024
+
+type patternParser struct {
+	pattern string
+	offset  int
+}
+
+func (p *patternParser) errorf(offset int, format string, args ...interface{}) error {
+	return &PatternError{Pattern: p.pattern, Offset: offset, Msg: fmt.Sprintf(format, args...)}
+}
+
+// next returns the rune at the current offset and advances past it.
+func (p *patternParser) next() rune {
+	r, size := utf8.DecodeRuneInString(p.pattern[p.offset:])
+	p.offset += size
+	return r
+}
+
+func (p *patternParser) done() bool { return p.offset >= len(p.pattern) }
+
+func (p *patternParser) parse() ([][]string, error) {
+	var pools [][]string
+	repeated := false
+	for !p.done() {
+		start := p.offset
+		r := p.next()
+		switch r {
+		case '\\':
+			if p.done() {
+				return nil, p.errorf(start, "trailing backslash")
+			}
+			if e := p.next(); e == 'd' {
+				pools = append(pools, runePool(digitChars))
+			} else {
+				pools = append(pools, []string{string(e)})
+			}
+		case '?':
+			if p.done() {
+				return nil, p.errorf(start, "incomplete mask token")
+			}
+			if c := p.next(); c == '?' {
+				pools = append(pools, []string{"?"})
+			} else if class, ok := maskClasses[c]; ok {
+				pools = append(pools, runePool(class))
+			} else {
+				return nil, p.errorf(start, "unknown mask token ?%c", c)
+			}
+		case '[':
+			pool, err := p.parseClass(start)
+			if err != nil {
+				return nil, err
+			}
+			pools = append(pools, pool)
+		case '{':
+			if len(pools) == 0 || repeated {
+				return nil, p.errorf(start, "repetition without a preceding position")
+			}
+			n, err := p.parseRepeat(start)
+			if err != nil {
+				return nil, err
+			}
+			last := pools[len(pools)-1]
+			pools = pools[:len(pools)-1]
+			for i := 0; i < n; i++ {
+				pools = append(pools, last)
+			}
+		case '(', ')', '|', '*', '+', '.', '^', '$', ']', '}':
+			return nil, p.errorf(start, "unsupported syntax %q; escape it to match it literally", r)
+		default:
+			pools = append(pools, []string{string(r)})
+		}
+		repeated = r == '{'
+	}
+	return pools, nil
+}
+
+// parseClass parses the remainder of a character class that began at start.
+func (p *patternParser) parseClass(start int) ([]string, error) {
+	var chars []rune
+	for {
+		if p.done() {
+			return nil, p.errorf(start, "unterminated character class")
+		}
+
+		offset := p.offset
+		r := p.next()
+		switch {
+		case r == ']':
+			if len(chars) == 0 {
+				return nil, p.errorf(start, "empty character class")
+			}
+			return Dedup(runePool(string(chars))), nil
+		case r == '^' && offset == start+1:
+			return nil, p.errorf(offset, "negated character classes are not supported")
+		case r == '\\':
+			if p.done() {
+				return nil, p.errorf(offset, "trailing backslash")
+			}
+			r = p.next()
+		}
+
+		// A dash between two characters forms a range.
+		if strings.HasPrefix(p.pattern[p.offset:], "-") && !strings.HasPrefix(p.pattern[p.offset:], "-]") {
+			p.offset++
+			if p.done() {
+				return nil, p.errorf(start, "unterminated character class")
+			}
+			hi := p.next()
+			if hi == '\\' {
+				if p.done() {
+					return nil, p.errorf(start, "trailing backslash")
+				}
+				hi = p.next()
+			}
+			if hi < r {
+				return nil, p.errorf(offset, "invalid range %c-%c", r, hi)
+			}
+			for c := r; c <= hi; c++ {
+				chars = append(chars, c)
+			}
+			continue
+		}
+		chars = append(chars, r)
+	}
+}
+
+// parseRepeat parses the remainder of a repetition that began at start.
+func (p *patternParser) parseRepeat(start int) (int, error) {
+	end := strings.IndexByte(p.pattern[p.offset:], '}')
+	if end < 0 {
+		return 0, p.errorf(start, "unterminated repetition")
+	}
+
+	digits := p.pattern[p.offset : p.offset+end]
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 0 {
+		return 0, p.errorf(start, "invalid repetition count %q", digits)
+	}
+	if n > maxPatternRepeat {
+		return 0, p.errorf(start, "repetition count %d exceeds %d", n, maxPatternRepeat)
+	}
+	p.offset += end + 1
+	return n, nil
+}
+
+// runePool returns each rune of s as a separate string.
+func runePool(s string) []string {
+	pool := make([]string, 0, len(s))
+	for _, r := range s {
+		pool = append(pool, string(r))
+	}
+	return pool
+}
@@ -0,0 +1,192 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExpandPattern(t *testing.T) {
+	table := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"", []string{""}},
+		{"abc", []string{"abc"}},
+		{"[a-c]", []string{"a", "b", "c"}},
+		{"[ca]x", []string{"cx", "ax"}},
+		{"[aa-b]", []string{"a", "b"}},
+		{"[a-]", []string{"a", "-"}},
+		{`[\]\-]`, []string{"]", "-"}},
+		{"[ab]{2}", []string{"aa", "ab", "ba", "bb"}},
+		{"x{0}y", []string{"y"}},
+		{"?d", []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}},
+		{`\d`, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}},
+		{"??", []string{"?"}},
+		{`host\.[12]`, []string{"host.1", "host.2"}},
+		{"[é-ë]", []string{"é", "ê", "ë"}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.pattern, func(t *testing.T) {
+			actual, err := ExpandPattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("err = %s", err)
+			}
+			if !SliceEqual(actual, tt.expected) {
+				t.Errorf("actual = %q; want = %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParsePatternErrors(t *testing.T) {
+	table := []struct {
+		pattern string
+		offset  int
+	}{
+		{`ab\`, 2},
+		{"a?", 1},
+		{"?x", 0},
+		{"a?é", 1},
+		{"a[bc", 1},
+		{"[]", 0},
+		{"[^a]", 1},
+		{"[c-a]", 1},
+		{"{2}", 0},
+		{"a{2", 1},
+		{"a{2}{3}", 4},
+		{"a{0}{3}", 4},
+		{"a{x}", 1},
+		{"a{-1}", 1},
+		{"a{99999}", 1},
+		{"a|b", 1},
+		{"a.b", 1},
+		{"a*", 1},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := ParsePattern(tt.pattern)
+			var patternErr *PatternError
+			if !errors.As(err, &patternErr) {
+				t.Fatalf("err = %v; want = *PatternError", err)
+			}
+			if patternErr.Offset != tt.offset {
+				t.Errorf("Offset = %d; want = %d (%s)", patternErr.Offset, tt.offset, err)
+			}
+		})
+	}
+}
+
+func TestParsePatternMaskErrorMessage(t *testing.T) {
+	_, err := ParsePattern("a?é")
+	if err == nil || !strings.Contains(err.Error(), "unknown mask token ?é") {
+		t.Errorf("err = %v; want it to name the mask token ?é", err)
+	}
+}
+
+func TestPatternCount(t *testing.T) {
+	table := []struct {
+		pattern  string
+		expected uint64
+	}{
+		{"", 1},
+		{"[a-c][0-9]{2}", 300},
+		{"?l?d?d", 2600},
+		{"?a", 95},
+		{"?h{4}", 65536},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := ParsePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("err = %s", err)
+			}
+			actual, err := p.Count()
+			if err != nil {
+				t.Fatalf("err = %s", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("actual = %d; want = %d", actual, tt.expected)
+			}
+			if expanded := uint64(len(p.Expand())); expanded != tt.expected {
+				t.Errorf("len(Expand()) = %d; want = %d", expanded, tt.expected)
+			}
+		})
+	}
+
+	p, _ := ParsePattern("?a{10}")
+	if _, err := p.Count(); err != ErrOverflow {
+		t.Errorf("err = %v; want = %v", err, ErrOverflow)
+	}
+}
+
+func TestExpandPatternLimited(t *testing.T) {
+	actual, err := ExpandPatternLimited("[ab]é{2}", Limits{MaxResults: 2})
+	if err != nil {
+		t.Fatalf("err = %s", err)
+	}
+	if !SliceEqual(actual, []string{"aéé", "béé"}) {
+		t.Errorf("actual = %q", actual)
+	}
+
+	// The estimate counts the bytes of every string and its header.
+	expected := int64(len(actual)) * stringHeaderSize
+	for _, x := range actual {
+		expected += int64(len(x))
+	}
+	_, err = ExpandPatternLimited("[ab]é{2}", Limits{MaxBytes: 1})
+	var tooMany *TooManyResultsError
+	if !errors.As(err, &tooMany) {
+		t.Fatalf("err = %v; want = *TooManyResultsError", err)
+	}
+	if tooMany.Bytes.Int64() != expected {
+		t.Errorf("Bytes = %s; want = %d", tooMany.Bytes, expected)
+	}
+
+	_, err = ExpandPatternLimited("?a?a?a?a?a?a", Limits{MaxResults: 1 << 20})
+	if !errors.As(err, &tooMany) {
+		t.Fatalf("err = %v; want = *TooManyResultsError", err)
+	}
+	if tooMany.Count.String() != "735091890625" {
+		t.Errorf("Count = %s; want = 735091890625", tooMany.Count)
+	}
+
+	var patternErr *PatternError
+	if _, err := ExpandPatternLimited("a{", Limits{}); !errors.As(err, &patternErr) {
+		t.Errorf("err = %v; want = *PatternError", err)
+	}
+}
+
+func TestPatternIter(t *testing.T) {
+	p, err := ParsePattern("id-[a-c]?d{3}")
+	if err != nil {
+		t.Fatalf("err = %s", err)
+	}
+
+	it := p.Iter()
+	var first []string
+	for len(first) < 3 && it.Next() {
+		first = append(first, it.Value())
+	}
+	expected := []string{"id-a000", "id-a001", "id-a002"}
+	if !SliceEqual(first, expected) {
+		t.Errorf("actual = %v; want = %v", first, expected)
+	}
+}
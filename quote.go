@@ -0,0 +1,153 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// QuoteOptions configures quote-aware splitting.
+type QuoteOptions struct {
+	// Quotes is the set of characters that open and close a quoted run. A
+	// run is closed only by the character that opened it.
+	Quotes string
+
+	// Escape causes the character following it to be taken literally, both
+	// inside and outside of quotes. The zero value disables escaping.
+	Escape rune
+}
+
+// DefaultQuoteOptions quotes with double or single quotes and escapes with a
+// backslash.
+var DefaultQuoteOptions = QuoteOptions{Quotes: `"'`, Escape: '\\'}
+
+// QuoteError is returned when a string cannot be split because of malformed
+// quoting.
+type QuoteError struct {
+	Input  string
+	Offset int
+	Msg    string
+}
+
+func (e *QuoteError) Error() string {
+	return fmt.Sprintf("invalid quoted string %q at byte offset %d: %s", e.Input, e.Offset, e.Msg)
+}
+
+// SplitQuoted splits the string `s` around each instance of sep that is not
+// quoted or escaped, as configured by opts. Quote and escape characters are
+// removed from the returned segments.
+//
+//	SplitQuoted(`name="a,b",age=3`, ",", DefaultQuoteOptions)
+//	// []string{"name=a,b", "age=3"}
+//
+// Returns an error if sep is empty and a *QuoteError if a quote is not
+// terminated or the input ends with an escape character.
+func SplitQuoted(s, sep string, opts QuoteOptions) ([]string, error) {
+	return splitQuoted(s, sep, opts, -1)
+}
+
+// SplitExactQuoted is SplitExact, but splits with the semantics of
+// SplitQuoted.
+func SplitExactQuoted(s, sep string, opts QuoteOptions, vars ...*string) error {
+	segments, err := splitQuoted(s, sep, opts, -1)
+	if err != nil {
+		return err
+	}
+	if len(segments) != len(vars) {
+		return newUnpackLenError(len(vars), len(segments), false, s, sep)
+	}
+	return Unpack(segments, vars...)
+}
+
+// SplitIntoQuoted is SplitInto, but splits with the semantics of
+// SplitQuoted. The remainder put into the final variable is kept verbatim,
+// including any quote and escape characters, but its quoting must still be
+// well-formed.
+func SplitIntoQuoted(s, sep string, opts QuoteOptions, vars ...*string) error {
+	segments, err := splitQuoted(s, sep, opts, len(vars))
+	if err != nil {
+		return err
+	}
+	if len(segments) != len(vars) {
+		return newUnpackLenError(len(vars), len(segments), true, s, sep)
+	}
+	return Unpack(segments, vars...)
+}
+
+// splitQuoted splits s into at most n segments, the last of which is the
+// unprocessed remainder, like strings.SplitN. If n is negative, there is no
+// limit. The remainder is scanned for malformed quoting all the same.
+func splitQuoted(s, sep string, opts QuoteOptions, n int) ([]string, error) {
+	if sep == "" {
+		return nil, errors.New("empty separator")
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	var (
+		segments   []string
+		segment    strings.Builder
+		quote      rune
+		quoteStart int
+		rest       = -1
+	)
+	if n == 1 {
+		rest = 0
+	}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case opts.Escape != 0 && r == opts.Escape:
+			if i+size == len(s) {
+				return nil, &QuoteError{Input: s, Offset: i, Msg: "unterminated escape"}
+			}
+			_, escaped := utf8.DecodeRuneInString(s[i+size:])
+			segment.WriteString(s[i+size : i+size+escaped])
+			i += size + escaped
+		case quote != 0:
+			if r != quote {
+				segment.WriteString(s[i : i+size])
+			} else {
+				quote = 0
+			}
+			i += size
+		case strings.ContainsRune(opts.Quotes, r):
+			quote, quoteStart = r, i
+			i += size
+		case rest < 0 && strings.HasPrefix(s[i:], sep):
+			segments = append(segments, segment.String())
+			segment.Reset()
+			i += len(sep)
+			if len(segments) == n-1 {
+				rest = i
+			}
+		default:
+			segment.WriteString(s[i : i+size])
+			i += size
+		}
+	}
+
+	if quote != 0 {
+		return nil, &QuoteError{Input: s, Offset: quoteStart, Msg: fmt.Sprintf("unterminated %c quote", quote)}
+	}
+	if rest >= 0 {
+		return append(segments, s[rest:]), nil
+	}
+	return append(segments, segment.String()), nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSplitQuoted(t *testing.T) {
+	table := []struct {
+		src      string
+		sep      string
+		expected []string
+	}{
+		{"", ",", []string{""}},
+		{"a,b", ",", []string{"a", "b"}},
+		{`name="a,b",age=3`, ",", []string{"name=a,b", "age=3"}},
+		{`'a"b',"c'd"`, ",", []string{`a"b`, `c'd`}},
+		{`a\,b,c`, ",", []string{"a,b", "c"}},
+		{`"a\"b"`, ",", []string{`a"b`}},
+		{`"",`, ",", []string{"", ""}},
+		{`x::"y::z"::`, "::", []string{"x", "y::z", ""}},
+		{`"é,ü",ß`, ",", []string{"é,ü", "ß"}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.src, func(t *testing.T) {
+			actual, err := SplitQuoted(tt.src, tt.sep, DefaultQuoteOptions)
+			if err != nil {
+				t.Fatalf("err = %s", err)
+			}
+			if fmt.Sprintf("%q", actual) != fmt.Sprintf("%q", tt.expected) {
+				t.Errorf("actual = %q; want = %q", actual, tt.expected)
+			}
+		})
+	}
+
+	actual, err := SplitQuoted(`'a,b'|c\|d`, ",", QuoteOptions{Quotes: "|"})
+	if err != nil {
+		t.Fatalf("err = %s", err)
+	}
+	if expected := []string{"'a", "b'c\\d"}; fmt.Sprintf("%q", actual) != fmt.Sprintf("%q", expected) {
+		t.Errorf("actual = %q; want = %q", actual, expected)
+	}
+}
+
+func TestSplitQuotedErrors(t *testing.T) {
+	table := []struct {
+		src    string
+		sep    string
+		offset int
+	}{
+		{`a,"b`, ",", 2},
+		{`a,'b"`, ",", 2},
+		{`"é",'`, ",", 5},
+		{`a\`, ",", 1},
+		{`"a\`, ",", 2},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := SplitQuoted(tt.src, tt.sep, DefaultQuoteOptions)
+			var quoteErr *QuoteError
+			if !errors.As(err, &quoteErr) {
+				t.Fatalf("err = %v; want = *QuoteError", err)
+			}
+			if quoteErr.Offset != tt.offset {
+				t.Errorf("Offset = %d; want = %d", quoteErr.Offset, tt.offset)
+			}
+		})
+	}
+}
+
+func TestSplitExactQuoted(t *testing.T) {
+	var key, value string
+	if err := SplitExactQuoted(`"a=b"=c`, "=", DefaultQuoteOptions, &key, &value); err != nil {
+		t.Fatalf("err = %s", err)
+	}
+	if key != "a=b" || value != "c" {
+		t.Errorf("actual = %q, %q", key, value)
+	}
+
+	if err := SplitExactQuoted(`a="b=c"=d`, "=", DefaultQuoteOptions, &key, &value); !errors.Is(err, ErrInconsistentUnpackLen) {
+		t.Errorf("err = %v; want = %v", err, ErrInconsistentUnpackLen)
+	}
+}
+
+func TestSplitIntoQuoted(t *testing.T) {
+	table := []struct {
+		src         string
+		expectedErr error
+		parts       []string
+	}{
+		{"", nil, []string{""}},
+		{`"a,b",c`, nil, []string{"a,b", "c"}},
+		{`"a,b",c,"d,e"`, nil, []string{"a,b", `c,"d,e"`}},
+		{`"a,b"`, ErrInconsistentUnpackLen, []string{"a,b", ""}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.src, func(t *testing.T) {
+			dests := make([]string, len(tt.parts))
+			vars := make([]*string, len(tt.parts))
+			for i := range dests {
+				vars[i] = &dests[i]
+			}
+
+			err := SplitIntoQuoted(tt.src, ",", DefaultQuoteOptions, vars...)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("err = %v; want = %v", err, tt.expectedErr)
+			}
+			if err == nil && fmt.Sprintf("%q", dests) != fmt.Sprintf("%q", tt.parts) {
+				t.Errorf("actual = %q; want = %q", dests, tt.parts)
+			}
+		})
+	}
+
+	errorCases := []struct {
+		src    string
+		vars   int
+		offset int
+	}{
+		{`"a,b`, 2, 0},
+		{`a,"b`, 2, 2},
+		{`"a,b",c,"d`, 2, 8},
+		{`a,b\`, 2, 3},
+		{`"a`, 1, 0},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.src, func(t *testing.T) {
+			vars := make([]*string, tt.vars)
+			for i := range vars {
+				vars[i] = new(string)
+			}
+
+			err := SplitIntoQuoted(tt.src, ",", DefaultQuoteOptions, vars...)
+			var quoteErr *QuoteError
+			if !errors.As(err, &quoteErr) {
+				t.Fatalf("err = %v; want = *QuoteError", err)
+			}
+			if quoteErr.Offset != tt.offset {
+				t.Errorf("Offset = %d; want = %d", quoteErr.Offset, tt.offset)
+			}
+		})
+	}
+}
+
+func TestSplitQuotedEmptySeparator(t *testing.T) {
+	_, err := SplitQuoted("a", "", DefaultQuoteOptions)
+	var quoteErr *QuoteError
+	if err == nil || errors.As(err, &quoteErr) {
+		t.Errorf("err = %v; want a non-*QuoteError", err)
+	}
+}
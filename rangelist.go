@@ -0,0 +1,231 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRange is returned when formatting a Range that is negative or
+// reversed.
+var ErrInvalidRange = errors.New("range must satisfy 0 <= Start <= End")
+
+// Range is an inclusive range of non-negative integers.
+type Range struct {
+	Start, End int
+}
+
+// RangeListError describes why a segment of a range list could not be
+// parsed.
+type RangeListError struct {
+	Segment string
+	Offset  int
+	Msg     string
+}
+
+func (e *RangeListError) Error() string {
+	return fmt.Sprintf("invalid range %q at byte offset %d: %s", e.Segment, e.Offset, e.Msg)
+}
+
+// ParseRangeList parses a comma-separated list of non-negative integers and
+// inclusive ranges of them, such as "1-5,7,9-11".
+//
+// The returned ranges are sorted, and ranges that overlap or are adjacent are
+// merged, so "3,1-2,2-4" parses into the single range 1-4. Whitespace around
+// each segment is ignored. If s is empty, nil is returned.
+//
+// Returns a *RangeListError if a segment is empty, is not an integer or a
+// range, is reversed, or overflows an int.
+func ParseRangeList(s string) ([]Range, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var ranges []Range
+	offset := 0
+	for _, segment := range strings.Split(s, ",") {
+		r, err := parseRange(segment, offset)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+		offset += len(segment) + 1
+	}
+	return mergeRanges(ranges), nil
+}
+
+// FormatRangeList formats ranges in the syntax parsed by ParseRangeList.
+//
+// The ranges are sorted and merged first, so that the result is the same
+// regardless of how the ranges were split up.
+//
+// Returns an error wrapping ErrInvalidRange if any range is negative or
+// reversed, since ParseRangeList would reject it.
+func FormatRangeList(ranges []Range) (string, error) {
+	for _, r := range ranges {
+		if r.Start < 0 || r.Start > r.End {
+			return "", fmt.Errorf("%w: %d-%d", ErrInvalidRange, r.Start, r.End)
+		}
+	}
+	ranges = mergeRanges(append([]Range(nil), ranges...))
+
+	segments := make([]string, len(ranges))
+	for i, r := range ranges {
+		if r.Start == r.End {
+			segments[i] = strconv.Itoa(r.Start)
+			continue
+		}
+		segments[i] = strconv.Itoa(r.Start) + "-" + strconv.Itoa(r.End)
+	}
+	return strings.Join(segments, ","), nil
+}
+
+// ExpandRangeList parses a range list and returns every integer that it
+// contains, in ascending order and without duplicates.
+//
+// Returns a *TooManyResultsError instead if the result would exceed the
+// provided Limits.
+func ExpandRangeList(s string, limits Limits) ([]int, error) {
+	ranges, err := parseRangeListLimited(s, limits, strconv.IntSize/8)
+	if err != nil {
+		return nil, err
+	}
+	return expandRanges(ranges), nil
+}
+
+// ExpandRangeListStrings is ExpandRangeList, but formats every integer as a
+// string.
+func ExpandRangeListStrings(s string, limits Limits) ([]string, error) {
+	ranges, err := parseRangeListLimited(s, limits, stringHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+
+	xs := expandRanges(ranges)
+	ys := make([]string, len(xs))
+	for i, x := range xs {
+		ys[i] = strconv.Itoa(x)
+	}
+	return ys, nil
+}
+
+// parseRangeListLimited parses a range list, checking that expanding it into
+// a slice with elements of the provided size stays within limits.
+func parseRangeListLimited(s string, limits Limits, size int64) ([]Range, error) {
+	ranges, err := ParseRangeList(s)
+	if err != nil {
+		return nil, err
+	}
+
+	count := new(big.Int)
+	for _, r := range ranges {
+		count.Add(count, big.NewInt(int64(r.End-r.Start)))
+		count.Add(count, big.NewInt(1))
+	}
+	if err := limits.checkBytes(count, new(big.Int).Mul(count, big.NewInt(size))); err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+// expandRanges returns every integer in ranges.
+func expandRanges(ranges []Range) []int {
+	var xs []int
+	for _, r := range ranges {
+		for x := r.Start; ; x++ {
+			xs = append(xs, x)
+			if x == r.End {
+				break
+			}
+		}
+	}
+	return xs
+}
+
+// parseRange parses a single segment of a range list found at offset.
+func parseRange(segment string, offset int) (Range, error) {
+	fail := func(msg string) (Range, error) {
+		return Range{}, &RangeListError{Segment: segment, Offset: offset, Msg: msg}
+	}
+
+	trimmed := strings.TrimSpace(segment)
+	if trimmed == "" {
+		return fail("empty segment")
+	}
+
+	before, after, isRange := strings.Cut(trimmed, "-")
+	start, msg := parseRangeBound(before)
+	if msg != "" {
+		return fail(msg)
+	}
+	if !isRange {
+		return Range{start, start}, nil
+	}
+
+	end, msg := parseRangeBound(after)
+	if msg != "" {
+		return fail(msg)
+	}
+	if start > end {
+		return fail(fmt.Sprintf("start %d is greater than end %d", start, end))
+	}
+	return Range{start, end}, nil
+}
+
+// parseRangeBound parses one end of a range, returning a description of the
+// problem if it is invalid.
+func parseRangeBound(s string) (int, string) {
+	if s == "" {
+		return 0, "missing bound"
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return 0, fmt.Sprintf("%q is not a non-negative integer", s)
+		}
+	}
+
+	x, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Sprintf("%s overflows an int", s)
+	}
+	return x, ""
+}
+
+// mergeRanges sorts ranges in place and merges those that overlap or are
+// adjacent.
+func mergeRanges(ranges []Range) []Range {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start-1 <= last.End {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
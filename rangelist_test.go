@@ -0,0 +1,153 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestParseRangeList(t *testing.T) {
+	table := []struct {
+		src       string
+		expected  []Range
+		formatted string
+	}{
+		{"", nil, ""},
+		{"7", []Range{{7, 7}}, "7"},
+		{"1-5,7,9-11", []Range{{1, 5}, {7, 7}, {9, 11}}, "1-5,7,9-11"},
+		{"9-11, 1-5 ,7", []Range{{1, 5}, {7, 7}, {9, 11}}, "1-5,7,9-11"},
+		{"1-3,2-6", []Range{{1, 6}}, "1-6"},
+		{"1-3,4-6", []Range{{1, 6}}, "1-6"},
+		{"1,2,3", []Range{{1, 3}}, "1-3"},
+		{"5,5", []Range{{5, 5}}, "5"},
+		{"0-0", []Range{{0, 0}}, "0"},
+		{fmt.Sprintf("0,%d", math.MaxInt), []Range{{0, 0}, {math.MaxInt, math.MaxInt}}, fmt.Sprintf("0,%d", math.MaxInt)},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.src, func(t *testing.T) {
+			actual, err := ParseRangeList(tt.src)
+			if err != nil {
+				t.Fatalf("err = %s", err)
+			}
+			if fmt.Sprint(actual) != fmt.Sprint(tt.expected) {
+				t.Errorf("actual = %v; want = %v", actual, tt.expected)
+			}
+			formatted, err := FormatRangeList(actual)
+			if err != nil {
+				t.Fatalf("format err = %s", err)
+			}
+			if formatted != tt.formatted {
+				t.Errorf("formatted = %q; want = %q", formatted, tt.formatted)
+			}
+		})
+	}
+}
+
+func TestParseRangeListErrors(t *testing.T) {
+	table := []struct {
+		src     string
+		segment string
+		offset  int
+	}{
+		{"1,,2", "", 2},
+		{"1,", "", 2},
+		{"5-1", "5-1", 0},
+		{"1,x", "x", 2},
+		{"1-", "1-", 0},
+		{"-3", "-3", 0},
+		{"1-2-3", "1-2-3", 0},
+		{"+4", "+4", 0},
+		{"1,99999999999999999999", "99999999999999999999", 2},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := ParseRangeList(tt.src)
+			var rangeErr *RangeListError
+			if !errors.As(err, &rangeErr) {
+				t.Fatalf("err = %v; want = *RangeListError", err)
+			}
+			if rangeErr.Segment != tt.segment || rangeErr.Offset != tt.offset {
+				t.Errorf("Segment, Offset = %q, %d; want = %q, %d (%s)", rangeErr.Segment, rangeErr.Offset, tt.segment, tt.offset, err)
+			}
+		})
+	}
+}
+
+func TestFormatRangeList(t *testing.T) {
+	actual, err := FormatRangeList([]Range{{9, 11}, {1, 5}, {3, 7}})
+	if err != nil {
+		t.Fatalf("err = %s", err)
+	}
+	if actual != "1-7,9-11" {
+		t.Errorf("actual = %q; want = %q", actual, "1-7,9-11")
+	}
+
+	for _, ranges := range [][]Range{{{5, 3}}, {{-2, -1}}, {{1, 2}, {-1, 4}}} {
+		if _, err := FormatRangeList(ranges); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("FormatRangeList(%v) err = %v; want = %v", ranges, err, ErrInvalidRange)
+		}
+	}
+}
+
+func TestExpandRangeList(t *testing.T) {
+	actual, err := ExpandRangeListStrings("4-6,1,5", Limits{})
+	if err != nil {
+		t.Fatalf("err = %s", err)
+	}
+	expected := []string{"1", "4", "5", "6"}
+	if !SliceEqual(actual, expected) {
+		t.Errorf("actual = %v; want = %v", actual, expected)
+	}
+
+	if _, err := ExpandRangeList("3-1", Limits{}); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestExpandRangeListLimited(t *testing.T) {
+	if _, err := ExpandRangeList("1-4,6", Limits{MaxResults: 5}); err != nil {
+		t.Errorf("at MaxResults err = %s", err)
+	}
+
+	table := []struct {
+		description string
+		expand      func() error
+		count       string
+	}{
+		{"ints", func() error { _, err := ExpandRangeList("1-4,6", Limits{MaxResults: 4}); return err }, "5"},
+		{"strings", func() error { _, err := ExpandRangeListStrings("1-4,6", Limits{MaxResults: 4}); return err }, "5"},
+		{"max int", func() error {
+			_, err := ExpandRangeList(fmt.Sprintf("0-%d", math.MaxInt), Limits{MaxBytes: 1 << 30})
+			return err
+		}, "9223372036854775808"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			var tooMany *TooManyResultsError
+			if err := tt.expand(); !errors.As(err, &tooMany) {
+				t.Fatalf("err = %v; want = *TooManyResultsError", err)
+			}
+			if tooMany.Count.String() != tt.count {
+				t.Errorf("Count = %s; want = %s", tooMany.Count, tt.count)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrRankOutOfRange is returned when unranking a rank that is not less than
+// the number of tuples produced by the corresponding generator.
+var ErrRankOutOfRange = errors.New("the rank is out of range of the generated tuples")
+
+// ErrTupleNotFound is returned when ranking a tuple that the corresponding
+// generator never produces for the provided pool.
+var ErrTupleNotFound = errors.New("the tuple is not generated from the provided pool")
+
+// ErrOverflow is returned when a result does not fit into a uint64.
+var ErrOverflow = errors.New("the result overflows uint64")
+
+// UnrankPermutation returns the permutation at the provided zero-based rank
+// in the order produced by SlicePermutationsR, without generating any of the
+// permutations that precede it.
+//
+// Returns ErrRankOutOfRange if SlicePermutationsR would produce fewer than
+// rank+1 permutations.
+func UnrankPermutation(pool []string, r int, rank uint64) ([]string, error) {
+	return UnrankPermutationBig(pool, r, new(big.Int).SetUint64(rank))
+}
+
+// UnrankPermutationBig is UnrankPermutation for ranks that do not fit into a
+// uint64.
+func UnrankPermutationBig(pool []string, r int, rank *big.Int) ([]string, error) {
+	indices, err := unrankPermutationIndices(len(pool), r, rank)
+	if err != nil {
+		return nil, err
+	}
+	return pick(pool, indices), nil
+}
+
+// RankPermutation returns the zero-based rank of the provided permutation in
+// the order produced by SlicePermutationsR, where r is the length of the
+// permutation.
+//
+// If the pool contains duplicate values, the rank of the first matching
+// permutation is returned.
+//
+// Returns ErrTupleNotFound if the permutation is never produced and
+// ErrOverflow if the rank does not fit into a uint64.
+func RankPermutation(pool, permutation []string) (uint64, error) {
+	return uint64Result(RankPermutationBig(pool, permutation))
+}
+
+// RankPermutationBig is RankPermutation for ranks that do not fit into a
+// uint64.
+func RankPermutationBig(pool, permutation []string) (*big.Int, error) {
+	n, r := len(pool), len(permutation)
+	if CountPermutationsBig(n, r).Sign() == 0 {
+		return nil, ErrTupleNotFound
+	}
+
+	used := make([]bool, n)
+	indices := make([]int, r)
+	for i, x := range permutation {
+		p := 0
+		for p < n && (used[p] || pool[p] != x) {
+			p++
+		}
+		if p == n {
+			return nil, ErrTupleNotFound
+		}
+		indices[i] = p
+		used[p] = true
+	}
+
+	return rankPermutationIndices(n, indices), nil
+}
+
+// UnrankCombination returns the combination at the provided zero-based rank
+// in the order produced by SliceCombinationsR, without generating any of the
+// combinations that precede it.
+//
+// Returns ErrRankOutOfRange if SliceCombinationsR would produce fewer than
+// rank+1 combinations.
+func UnrankCombination(pool []string, r int, rank uint64) ([]string, error) {
+	return UnrankCombinationBig(pool, r, new(big.Int).SetUint64(rank))
+}
+
+// UnrankCombinationBig is UnrankCombination for ranks that do not fit into a
+// uint64.
+func UnrankCombinationBig(pool []string, r int, rank *big.Int) ([]string, error) {
+	indices, err := unrankCombinationIndices(len(pool), r, rank)
+	if err != nil {
+		return nil, err
+	}
+	return pick(pool, indices), nil
+}
+
+// RankCombination returns the zero-based rank of the provided combination in
+// the order produced by SliceCombinationsR, where r is the length of the
+// combination.
+//
+// If the pool contains duplicate values, the rank of the first matching
+// combination is returned.
+//
+// Returns ErrTupleNotFound if the combination is never produced and
+// ErrOverflow if the rank does not fit into a uint64.
+func RankCombination(pool, combination []string) (uint64, error) {
+	return uint64Result(RankCombinationBig(pool, combination))
+}
+
+// RankCombinationBig is RankCombination for ranks that do not fit into a
+// uint64.
+func RankCombinationBig(pool, combination []string) (*big.Int, error) {
+	n, r := len(pool), len(combination)
+	if CountCombinationsBig(n, r).Sign() == 0 {
+		return nil, ErrTupleNotFound
+	}
+
+	indices := make([]int, r)
+	p := 0
+	for i, x := range combination {
+		for p < n && pool[p] != x {
+			p++
+		}
+		if p == n {
+			return nil, ErrTupleNotFound
+		}
+		indices[i] = p
+		p++
+	}
+
+	return rankCombinationIndices(n, indices), nil
+}
+
+// UnrankCombinationWithReplacement returns the combination at the provided
+// zero-based rank in the order produced by SliceCombinationsWithReplacement,
+// without generating any of the combinations that precede it.
+//
+// Returns ErrRankOutOfRange if SliceCombinationsWithReplacement would produce
+// fewer than rank+1 combinations.
+func UnrankCombinationWithReplacement(pool []string, r int, rank uint64) ([]string, error) {
+	return UnrankCombinationWithReplacementBig(pool, r, new(big.Int).SetUint64(rank))
+}
+
+// UnrankCombinationWithReplacementBig is UnrankCombinationWithReplacement for
+// ranks that do not fit into a uint64.
+func UnrankCombinationWithReplacementBig(pool []string, r int, rank *big.Int) ([]string, error) {
+	indices, err := unrankCombinationWithReplacementIndices(len(pool), r, rank)
+	if err != nil {
+		return nil, err
+	}
+	return pick(pool, indices), nil
+}
+
+// RankCombinationWithReplacement returns the zero-based rank of the provided
+// combination in the order produced by SliceCombinationsWithReplacement,
+// where r is the length of the combination.
+//
+// If the pool contains duplicate values, the rank of the first matching
+// combination is returned.
+//
+// Returns ErrTupleNotFound if the combination is never produced and
+// ErrOverflow if the rank does not fit into a uint64.
+func RankCombinationWithReplacement(pool, combination []string) (uint64, error) {
+	return uint64Result(RankCombinationWithReplacementBig(pool, combination))
+}
+
+// RankCombinationWithReplacementBig is RankCombinationWithReplacement for
+// ranks that do not fit into a uint64.
+func RankCombinationWithReplacementBig(pool, combination []string) (*big.Int, error) {
+	n, r := len(pool), len(combination)
+	if CountCombinationsWithReplacementBig(n, r).Sign() == 0 {
+		return nil, ErrTupleNotFound
+	}
+
+	indices := make([]int, r)
+	p := 0
+	for i, x := range combination {
+		for p < n && pool[p] != x {
+			p++
+		}
+		if p == n {
+			return nil, ErrTupleNotFound
+		}
+		indices[i] = p
+	}
+
+	return rankCombinationWithReplacementIndices(n, indices), nil
+}
+
+// uint64Result converts the result of a *big.Int function into a uint64.
+func uint64Result(x *big.Int, err error) (uint64, error) {
+	if err != nil {
+		return 0, err
+	}
+	return toUint64(x)
+}
+
+// checkRank returns ErrRankOutOfRange unless 0 <= rank < count.
+func checkRank(rank, count *big.Int) error {
+	if rank.Sign() < 0 || rank.Cmp(count) >= 0 {
+		return ErrRankOutOfRange
+	}
+	return nil
+}
+
+func unrankPermutationIndices(n, r int, rank *big.Int) ([]int, error) {
+	if err := checkRank(rank, CountPermutationsBig(n, r)); err != nil {
+		return nil, err
+	}
+
+	rem := new(big.Int).Set(rank)
+	used := make([]bool, n)
+	indices := make([]int, r)
+	for i := range indices {
+		// Every choice at this position is followed by the same number of
+		// suffixes, so the quotient selects the choice directly.
+		q, m := new(big.Int).QuoRem(rem, permutationsBig(n-i-1, r-i-1), new(big.Int))
+		rem = m
+
+		k := int(q.Int64())
+		for v := 0; v < n; v++ {
+			if used[v] {
+				continue
+			}
+			if k == 0 {
+				indices[i] = v
+				used[v] = true
+				break
+			}
+			k--
+		}
+	}
+	return indices, nil
+}
+
+func rankPermutationIndices(n int, indices []int) *big.Int {
+	r := len(indices)
+	rank := new(big.Int)
+	used := make([]bool, n)
+	for i, p := range indices {
+		// Count the unused positions that would have been chosen first.
+		smaller := 0
+		for v := 0; v < p; v++ {
+			if !used[v] {
+				smaller++
+			}
+		}
+		used[p] = true
+
+		term := permutationsBig(n-i-1, r-i-1)
+		rank.Add(rank, term.Mul(term, big.NewInt(int64(smaller))))
+	}
+	return rank
+}
+
+func unrankCombinationIndices(n, r int, rank *big.Int) ([]int, error) {
+	if err := checkRank(rank, CountCombinationsBig(n, r)); err != nil {
+		return nil, err
+	}
+
+	rem := new(big.Int).Set(rank)
+	indices := make([]int, r)
+	c := 0
+	for i := range indices {
+		for {
+			// The number of combinations that have c at this position.
+			count := binomialBig(n-c-1, r-i-1)
+			if rem.Cmp(count) < 0 {
+				break
+			}
+			rem.Sub(rem, count)
+			c++
+		}
+		indices[i] = c
+		c++
+	}
+	return indices, nil
+}
+
+func rankCombinationIndices(n int, indices []int) *big.Int {
+	r := len(indices)
+	rank := new(big.Int)
+	c := 0
+	for i, p := range indices {
+		for ; c < p; c++ {
+			rank.Add(rank, binomialBig(n-c-1, r-i-1))
+		}
+		c++
+	}
+	return rank
+}
+
+func unrankCombinationWithReplacementIndices(n, r int, rank *big.Int) ([]int, error) {
+	if err := checkRank(rank, CountCombinationsWithReplacementBig(n, r)); err != nil {
+		return nil, err
+	}
+
+	rem := new(big.Int).Set(rank)
+	indices := make([]int, r)
+	c := 0
+	for i := range indices {
+		for {
+			// The number of combinations that have c at this position.
+			count := multichooseBig(n-c, r-i-1)
+			if rem.Cmp(count) < 0 {
+				break
+			}
+			rem.Sub(rem, count)
+			c++
+		}
+		indices[i] = c
+	}
+	return indices, nil
+}
+
+func rankCombinationWithReplacementIndices(n int, indices []int) *big.Int {
+	r := len(indices)
+	rank := new(big.Int)
+	c := 0
+	for i, p := range indices {
+		for ; c < p; c++ {
+			rank.Add(rank, multichooseBig(n-c, r-i-1))
+		}
+	}
+	return rank
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+type rankFuncs struct {
+	description string
+	generate    func([]string, int) [][]string
+	unrank      func([]string, int, uint64) ([]string, error)
+	rank        func([]string, []string) (uint64, error)
+}
+
+var rankTable = []rankFuncs{
+	{"permutations", SlicePermutationsR, UnrankPermutation, RankPermutation},
+	{"combinations", SliceCombinationsR, UnrankCombination, RankCombination},
+	{"combinations with replacement", SliceCombinationsWithReplacement, UnrankCombinationWithReplacement, RankCombinationWithReplacement},
+}
+
+func TestRankRoundTrip(t *testing.T) {
+	for _, tt := range rankTable {
+		for r := 0; r <= len(letters)+1; r++ {
+			t.Run(tt.description+" r = "+strconv.Itoa(r), func(t *testing.T) {
+				for i, expected := range tt.generate(letters, r) {
+					actual, err := tt.unrank(letters, r, uint64(i))
+					if err != nil {
+						t.Fatalf("unrank(%d) err = %s", i, err)
+					}
+					if !SliceEqual(actual, expected) {
+						t.Fatalf("unrank(%d) = %v; want = %v", i, actual, expected)
+					}
+
+					rank, err := tt.rank(letters, expected)
+					if err != nil {
+						t.Fatalf("rank(%v) err = %s", expected, err)
+					}
+					if rank != uint64(i) {
+						t.Fatalf("rank(%v) = %d; want = %d", expected, rank, i)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRankErrors(t *testing.T) {
+	for _, tt := range rankTable {
+		t.Run(tt.description, func(t *testing.T) {
+			total := uint64(len(tt.generate(letters, 3)))
+			if _, err := tt.unrank(letters, 3, total); err != ErrRankOutOfRange {
+				t.Errorf("unrank(%d) err = %v; want = %v", total, err, ErrRankOutOfRange)
+			}
+			if _, err := tt.unrank(letters, -1, 0); err != ErrRankOutOfRange {
+				t.Errorf("unrank with r = -1 err = %v; want = %v", err, ErrRankOutOfRange)
+			}
+			if _, err := tt.rank(letters, []string{"a", "z"}); err != ErrTupleNotFound {
+				t.Errorf("rank of unknown value err = %v; want = %v", err, ErrTupleNotFound)
+			}
+			if rank, err := tt.rank(letters, nil); rank != 0 || err != nil {
+				t.Errorf("rank of empty tuple = %d, %v; want = 0, <nil>", rank, err)
+			}
+		})
+	}
+}
+
+func TestRankDuplicates(t *testing.T) {
+	pool := []string{"a", "b", "a"}
+	table := []struct {
+		description string
+		rank        func([]string, []string) (uint64, error)
+		tuple       []string
+		expectedErr error
+		expected    uint64
+	}{
+		{"permutation", RankPermutation, []string{"a", "a"}, nil, 1},
+		{"combination", RankCombination, []string{"a", "a"}, nil, 1},
+		{"combination with replacement", RankCombinationWithReplacement, []string{"b", "a"}, nil, 4},
+		{"combination reusing a position", RankCombination, []string{"b", "b"}, ErrTupleNotFound, 0},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual, err := tt.rank(pool, tt.tuple)
+			if err != tt.expectedErr {
+				t.Fatalf("err = %v; want = %v", err, tt.expectedErr)
+			}
+			if actual != tt.expected {
+				t.Errorf("actual = %d; want = %d", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRankBig(t *testing.T) {
+	pool := make([]string, 25)
+	for i := range pool {
+		pool[i] = strconv.Itoa(100 + i)
+	}
+	reversed := make([]string, len(pool))
+	for i, x := range pool {
+		reversed[len(pool)-1-i] = x
+	}
+
+	// 25! overflows a uint64.
+	last := new(big.Int).MulRange(1, 25)
+	last.Sub(last, big.NewInt(1))
+
+	actual, err := UnrankPermutationBig(pool, len(pool), last)
+	if err != nil {
+		t.Fatalf("err = %s", err)
+	}
+	if !SliceEqual(actual, reversed) {
+		t.Errorf("actual = %v; want = %v", actual, reversed)
+	}
+
+	rank, err := RankPermutationBig(pool, reversed)
+	if err != nil {
+		t.Fatalf("err = %s", err)
+	}
+	if rank.Cmp(last) != 0 {
+		t.Errorf("rank = %s; want = %s", rank, last)
+	}
+
+	if _, err := RankPermutation(pool, reversed); err != ErrOverflow {
+		t.Errorf("err = %v; want = %v", err, ErrOverflow)
+	}
+}
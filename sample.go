@@ -0,0 +1,152 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"sort"
+)
+
+// ErrSampleTooLarge is returned when more distinct tuples are requested than
+// the corresponding generator produces.
+var ErrSampleTooLarge = errors.New("the sample is larger than the number of generated tuples")
+
+// SamplePermutations returns k permutations chosen uniformly at random from
+// those produced by SlicePermutationsR.
+//
+// The permutations are drawn independently, so the same permutation may be
+// returned more than once. If SlicePermutationsR produces nothing or k is
+// less than or equal to 0, nil is returned.
+//
+// The randomness is drawn entirely from rng, so a seeded rng yields
+// reproducible samples.
+func SamplePermutations(rng *rand.Rand, pool []string, r, k int) [][]string {
+	ranks := sampleRanks(rng, CountPermutationsBig(len(pool), r), k)
+	return unrankAll(pool, r, ranks, unrankPermutationIndices)
+}
+
+// SampleDistinctPermutations returns k different permutations chosen
+// uniformly at random from those produced by SlicePermutationsR.
+//
+// The permutations are returned in the order that SlicePermutationsR
+// produces them. If k is less than or equal to 0, nil is returned.
+//
+// The randomness is drawn entirely from rng, so a seeded rng yields
+// reproducible samples.
+//
+// Returns ErrSampleTooLarge if SlicePermutationsR produces fewer than k
+// permutations.
+func SampleDistinctPermutations(rng *rand.Rand, pool []string, r, k int) ([][]string, error) {
+	ranks, err := sampleDistinctRanks(rng, CountPermutationsBig(len(pool), r), k)
+	if err != nil {
+		return nil, err
+	}
+	return unrankAll(pool, r, ranks, unrankPermutationIndices), nil
+}
+
+// SampleCombinations returns k combinations chosen uniformly at random from
+// those produced by SliceCombinationsR.
+//
+// The combinations are drawn independently, so the same combination may be
+// returned more than once. If SliceCombinationsR produces nothing or k is
+// less than or equal to 0, nil is returned.
+//
+// The randomness is drawn entirely from rng, so a seeded rng yields
+// reproducible samples.
+func SampleCombinations(rng *rand.Rand, pool []string, r, k int) [][]string {
+	ranks := sampleRanks(rng, CountCombinationsBig(len(pool), r), k)
+	return unrankAll(pool, r, ranks, unrankCombinationIndices)
+}
+
+// SampleDistinctCombinations returns k different combinations chosen
+// uniformly at random from those produced by SliceCombinationsR.
+//
+// The combinations are returned in the order that SliceCombinationsR
+// produces them. If k is less than or equal to 0, nil is returned.
+//
+// The randomness is drawn entirely from rng, so a seeded rng yields
+// reproducible samples.
+//
+// Returns ErrSampleTooLarge if SliceCombinationsR produces fewer than k
+// combinations.
+func SampleDistinctCombinations(rng *rand.Rand, pool []string, r, k int) ([][]string, error) {
+	ranks, err := sampleDistinctRanks(rng, CountCombinationsBig(len(pool), r), k)
+	if err != nil {
+		return nil, err
+	}
+	return unrankAll(pool, r, ranks, unrankCombinationIndices), nil
+}
+
+// sampleRanks draws k ranks in [0, count) independently.
+func sampleRanks(rng *rand.Rand, count *big.Int, k int) []*big.Int {
+	if k <= 0 || count.Sign() == 0 {
+		return nil
+	}
+
+	ranks := make([]*big.Int, k)
+	for i := range ranks {
+		ranks[i] = new(big.Int).Rand(rng, count)
+	}
+	return ranks
+}
+
+// sampleDistinctRanks draws k different ranks in [0, count) and returns them
+// in ascending order.
+//
+// This is Robert Floyd's algorithm, which makes exactly k draws regardless of
+// how close k is to count.
+func sampleDistinctRanks(rng *rand.Rand, count *big.Int, k int) ([]*big.Int, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	if count.Cmp(big.NewInt(int64(k))) < 0 {
+		return nil, ErrSampleTooLarge
+	}
+
+	seen := make(map[string]struct{}, k)
+	ranks := make([]*big.Int, 0, k)
+	j := new(big.Int).Sub(count, big.NewInt(int64(k)))
+	for i := 0; i < k; i++ {
+		// Choose from [0, j], falling back to j itself when the choice has
+		// already been taken.
+		rank := new(big.Int).Rand(rng, new(big.Int).Add(j, big.NewInt(1)))
+		if _, alreadyExists := seen[rank.String()]; alreadyExists {
+			rank.Set(j)
+		}
+		seen[rank.String()] = struct{}{}
+		ranks = append(ranks, rank)
+		j.Add(j, big.NewInt(1))
+	}
+
+	sort.Slice(ranks, func(a, b int) bool { return ranks[a].Cmp(ranks[b]) < 0 })
+	return ranks, nil
+}
+
+// unrankAll converts each rank into its tuple of pool values.
+func unrankAll(pool []string, r int, ranks []*big.Int, unrank func(n, r int, rank *big.Int) ([]int, error)) [][]string {
+	if len(ranks) == 0 {
+		return nil
+	}
+
+	ys := make([][]string, len(ranks))
+	for i, rank := range ranks {
+		// Every rank was drawn below the count, so this cannot fail.
+		indices, _ := unrank(len(pool), r, rank)
+		ys[i] = pick(pool, indices)
+	}
+	return ys
+}
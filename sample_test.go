@@ -0,0 +1,103 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+type sampleFuncs struct {
+	description    string
+	generate       func([]string, int) [][]string
+	sample         func(*rand.Rand, []string, int, int) [][]string
+	sampleDistinct func(*rand.Rand, []string, int, int) ([][]string, error)
+}
+
+var sampleTable = []sampleFuncs{
+	{"permutations", SlicePermutationsR, SamplePermutations, SampleDistinctPermutations},
+	{"combinations", SliceCombinationsR, SampleCombinations, SampleDistinctCombinations},
+}
+
+func TestSample(t *testing.T) {
+	for _, tt := range sampleTable {
+		t.Run(tt.description, func(t *testing.T) {
+			all := tt.generate(letters, 3)
+			valid := make(map[string]struct{}, len(all))
+			for _, x := range all {
+				valid[strings.Join(x, ",")] = struct{}{}
+			}
+
+			actual := tt.sample(rand.New(rand.NewSource(1)), letters, 3, 1000)
+			if len(actual) != 1000 {
+				t.Fatalf("len(actual) = %d; want = 1000", len(actual))
+			}
+			seen := make(map[string]struct{})
+			for _, x := range actual {
+				key := strings.Join(x, ",")
+				if _, ok := valid[key]; !ok {
+					t.Fatalf("%v is not generated", x)
+				}
+				seen[key] = struct{}{}
+			}
+			if len(seen) != len(all) {
+				t.Errorf("sampled %d different tuples; want = %d", len(seen), len(all))
+			}
+
+			again := tt.sample(rand.New(rand.NewSource(1)), letters, 3, 1000)
+			if !MatrixEqual(actual, again) {
+				t.Errorf("samples with the same seed differ")
+			}
+
+			if empty := tt.sample(rand.New(rand.NewSource(1)), letters, 6, 1); empty != nil {
+				t.Errorf("sample of nothing = %v; want = nil", empty)
+			}
+		})
+	}
+}
+
+func TestSampleDistinct(t *testing.T) {
+	for _, tt := range sampleTable {
+		t.Run(tt.description, func(t *testing.T) {
+			all := tt.generate(letters, 3)
+			for k := 0; k <= len(all); k++ {
+				actual, err := tt.sampleDistinct(rand.New(rand.NewSource(int64(k))), letters, 3, k)
+				if err != nil {
+					t.Fatalf("k = %d: err = %s", k, err)
+				}
+				if len(actual) != k {
+					t.Fatalf("k = %d: len(actual) = %d", k, len(actual))
+				}
+
+				// Samples must be a subsequence of the generated tuples.
+				i := 0
+				for _, x := range actual {
+					for i < len(all) && !SliceEqual(all[i], x) {
+						i++
+					}
+					if i == len(all) {
+						t.Fatalf("k = %d: %v is out of order or repeated in %v", k, x, actual)
+					}
+					i++
+				}
+			}
+
+			if _, err := tt.sampleDistinct(rand.New(rand.NewSource(1)), letters, 3, len(all)+1); err != ErrSampleTooLarge {
+				t.Errorf("err = %v; want = %v", err, ErrSampleTooLarge)
+			}
+		})
+	}
+}
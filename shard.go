@@ -0,0 +1,248 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+)
+
+// ErrInvalidShardCount is returned when a walk is requested over fewer than
+// one Shard.
+var ErrInvalidShardCount = errors.New("the number of shards must be positive")
+
+// Shard is a contiguous range of ranks, from Start inclusive to End
+// exclusive, in the order produced by one of the combinatorics generators.
+type Shard struct {
+	Index      int
+	Start, End *big.Int
+}
+
+// Len returns the number of ranks in the Shard.
+func (s Shard) Len() *big.Int { return new(big.Int).Sub(s.End, s.Start) }
+
+// Shards splits count ranks into k contiguous Shards whose lengths differ by
+// at most one. If k is larger than count, some of the Shards are empty.
+//
+// The boundaries depend only on count and k, so separate processes that agree
+// on both can each walk a different Shard of the same space.
+//
+// If k is less than or equal to 0, nil is returned.
+func Shards(count *big.Int, k int) []Shard {
+	if k <= 0 {
+		return nil
+	}
+
+	size, extra := new(big.Int).QuoRem(count, big.NewInt(int64(k)), new(big.Int))
+	shards := make([]Shard, k)
+	start := new(big.Int)
+	for i := range shards {
+		end := new(big.Int).Add(start, size)
+		if big.NewInt(int64(i)).Cmp(extra) < 0 {
+			end.Add(end, big.NewInt(1))
+		}
+		shards[i] = Shard{Index: i, Start: start, End: end}
+		start = new(big.Int).Set(end)
+	}
+	return shards
+}
+
+// SlicePermutationsRShardIter returns an Iterator over the permutations that
+// SlicePermutationsR produces within the provided Shard.
+//
+// The first permutation is found without generating any that precede it.
+func SlicePermutationsRShardIter(pool []string, r int, shard Shard) *Iterator {
+	start, last, ok := shardIndices(len(pool), r, shard, unrankPermutationIndices)
+	if !ok {
+		return &Iterator{}
+	}
+
+	p := permutationIndicesFrom(len(pool), start)
+	return shardIterator(pool, p.indices, last, func([]int) bool {
+		return p.next()
+	})
+}
+
+// SliceCombinationsRShardIter returns an Iterator over the combinations that
+// SliceCombinationsR produces within the provided Shard.
+//
+// The first combination is found without generating any that precede it.
+func SliceCombinationsRShardIter(pool []string, r int, shard Shard) *Iterator {
+	start, last, ok := shardIndices(len(pool), r, shard, unrankCombinationIndices)
+	if !ok {
+		return &Iterator{}
+	}
+
+	n := len(pool)
+	return shardIterator(pool, start, last, func(indices []int) bool {
+		return nextCombination(indices, n)
+	})
+}
+
+// SliceCombinationsWithReplacementShardIter returns an Iterator over the
+// combinations that SliceCombinationsWithReplacement produces within the
+// provided Shard.
+//
+// The first combination is found without generating any that precede it.
+func SliceCombinationsWithReplacementShardIter(pool []string, r int, shard Shard) *Iterator {
+	start, last, ok := shardIndices(len(pool), r, shard, unrankCombinationWithReplacementIndices)
+	if !ok {
+		return &Iterator{}
+	}
+
+	n := len(pool)
+	return shardIterator(pool, start, last, func(indices []int) bool {
+		return nextCombinationWithReplacement(indices, n)
+	})
+}
+
+// SlicePermutationsRParallel calls fn with every permutation that
+// SlicePermutationsR produces, walking k Shards concurrently.
+//
+// See SliceCombinationsRParallel for the calling and error semantics.
+func SlicePermutationsRParallel(ctx context.Context, pool []string, r, k int, fn func([]string) error) error {
+	return walkShards(ctx, CountPermutationsBig(len(pool), r), k, func(shard Shard) *Iterator {
+		return SlicePermutationsRShardIter(pool, r, shard)
+	}, fn)
+}
+
+// SliceCombinationsRParallel calls fn with every combination that
+// SliceCombinationsR produces, walking k Shards concurrently.
+//
+// Each Shard is walked in order by its own goroutine, so fn must be safe to
+// call concurrently. No more goroutines are started than there are
+// combinations. The first error returned by fn stops every goroutine and is
+// returned. If ctx is done before the walk completes, ctx.Err() is returned.
+//
+// Returns ErrInvalidShardCount, without calling fn, if k is less than 1.
+func SliceCombinationsRParallel(ctx context.Context, pool []string, r, k int, fn func([]string) error) error {
+	return walkShards(ctx, CountCombinationsBig(len(pool), r), k, func(shard Shard) *Iterator {
+		return SliceCombinationsRShardIter(pool, r, shard)
+	}, fn)
+}
+
+// SliceCombinationsWithReplacementParallel calls fn with every combination
+// that SliceCombinationsWithReplacement produces, walking k Shards
+// concurrently.
+//
+// See SliceCombinationsRParallel for the calling and error semantics.
+func SliceCombinationsWithReplacementParallel(ctx context.Context, pool []string, r, k int, fn func([]string) error) error {
+	return walkShards(ctx, CountCombinationsWithReplacementBig(len(pool), r), k, func(shard Shard) *Iterator {
+		return SliceCombinationsWithReplacementShardIter(pool, r, shard)
+	}, fn)
+}
+
+// walkShards splits count ranks into at most k Shards and concurrently calls
+// fn with the values of each Shard's Iterator, returning the first error
+// encountered.
+func walkShards(ctx context.Context, count *big.Int, k int, open func(Shard) *Iterator, fn func([]string) error) error {
+	if k <= 0 {
+		return ErrInvalidShardCount
+	}
+	if count.Cmp(big.NewInt(int64(k))) < 0 {
+		k = int(count.Int64())
+	}
+	shards := Shards(count, k)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard Shard) {
+			defer wg.Done()
+			for it := open(shard); it.Next(); {
+				if err := ctx.Err(); err != nil {
+					fail(err)
+					return
+				}
+				if err := fn(it.Value()); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}(shard)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// shardIndices returns the positions of the first and last tuples in the
+// Shard. It returns false if the Shard is empty or out of range.
+func shardIndices(n, r int, shard Shard, unrank func(n, r int, rank *big.Int) ([]int, error)) (start, last []int, ok bool) {
+	if shard.Start.Cmp(shard.End) >= 0 {
+		return nil, nil, false
+	}
+
+	start, err := unrank(n, r, shard.Start)
+	if err != nil {
+		return nil, nil, false
+	}
+	last, err = unrank(n, r, new(big.Int).Sub(shard.End, big.NewInt(1)))
+	if err != nil {
+		return nil, nil, false
+	}
+	return start, last, true
+}
+
+// shardIterator is indexIterator, but stops after producing the values found
+// at last.
+func shardIterator(pool []string, indices, last []int, next func([]int) bool) *Iterator {
+	done := false
+	it := indexIterator(pool, indices, next)
+	return &Iterator{next: func() ([]string, bool) {
+		if done || !it.Next() {
+			return nil, false
+		}
+		done = intsEqual(indices, last)
+		return it.Value(), true
+	}}
+}
+
+// permutationIndicesFrom returns permutationIndices positioned at indices.
+func permutationIndicesFrom(n int, indices []int) *permutationIndices {
+	p := &permutationIndices{indices: indices, used: make([]bool, n)}
+	for _, i := range indices {
+		p.used[i] = true
+	}
+	return p
+}
+
+func intsEqual(xs, ys []int) bool {
+	if len(xs) != len(ys) {
+		return false
+	}
+	for i, x := range xs {
+		if x != ys[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,201 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type shardFuncs struct {
+	description string
+	generate    func([]string, int) [][]string
+	shardIter   func([]string, int, Shard) *Iterator
+	parallel    func(context.Context, []string, int, int, func([]string) error) error
+}
+
+var shardTable = []shardFuncs{
+	{"permutations", SlicePermutationsR, SlicePermutationsRShardIter, SlicePermutationsRParallel},
+	{"combinations", SliceCombinationsR, SliceCombinationsRShardIter, SliceCombinationsRParallel},
+	{"combinations with replacement", SliceCombinationsWithReplacement, SliceCombinationsWithReplacementShardIter, SliceCombinationsWithReplacementParallel},
+}
+
+func TestShards(t *testing.T) {
+	table := []struct {
+		count    int64
+		k        int
+		expected []int64
+	}{
+		{10, 0, nil},
+		{0, 2, []int64{0, 0}},
+		{10, 1, []int64{10}},
+		{10, 3, []int64{4, 3, 3}},
+		{2, 4, []int64{1, 1, 0, 0}},
+	}
+
+	for _, tt := range table {
+		t.Run(fmt.Sprintf("count = %d k = %d", tt.count, tt.k), func(t *testing.T) {
+			shards := Shards(big.NewInt(tt.count), tt.k)
+			if len(shards) != len(tt.expected) {
+				t.Fatalf("len(shards) = %d; want = %d", len(shards), len(tt.expected))
+			}
+
+			next := new(big.Int)
+			for i, shard := range shards {
+				if shard.Index != i {
+					t.Errorf("shards[%d].Index = %d", i, shard.Index)
+				}
+				if shard.Start.Cmp(next) != 0 {
+					t.Errorf("shards[%d].Start = %s; want = %s", i, shard.Start, next)
+				}
+				if shard.Len().Int64() != tt.expected[i] {
+					t.Errorf("shards[%d].Len() = %s; want = %d", i, shard.Len(), tt.expected[i])
+				}
+				next = shard.End
+			}
+		})
+	}
+}
+
+func TestShardsDoNotAlias(t *testing.T) {
+	shards := Shards(big.NewInt(10), 2)
+	shards[1].Start.Add(shards[1].Start, big.NewInt(2))
+	if shards[0].End.Int64() != 5 {
+		t.Errorf("shards[0].End = %s; want = 5", shards[0].End)
+	}
+}
+
+func TestShardIterConcatenates(t *testing.T) {
+	for _, tt := range shardTable {
+		for k := 1; k <= 8; k++ {
+			t.Run(fmt.Sprintf("%s k = %d", tt.description, k), func(t *testing.T) {
+				expected := tt.generate(letters, 3)
+
+				var actual [][]string
+				for _, shard := range Shards(big.NewInt(int64(len(expected))), k) {
+					actual = append(actual, collect(tt.shardIter(letters, 3, shard))...)
+				}
+				if !MatrixEqual(actual, expected) {
+					t.Errorf("actual = %v; want = %v", actual, expected)
+				}
+			})
+		}
+	}
+}
+
+func TestParallel(t *testing.T) {
+	for _, tt := range shardTable {
+		t.Run(tt.description, func(t *testing.T) {
+			var (
+				mu     sync.Mutex
+				actual []string
+			)
+			err := tt.parallel(context.Background(), letters, 3, 4, func(x []string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				actual = append(actual, strings.Join(x, ","))
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("err = %s", err)
+			}
+
+			var expected []string
+			for _, x := range tt.generate(letters, 3) {
+				expected = append(expected, strings.Join(x, ","))
+			}
+			sort.Strings(actual)
+			if !SliceEqual(actual, expected) {
+				t.Errorf("actual = %v; want = %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestParallelShardCount(t *testing.T) {
+	for _, tt := range shardTable {
+		t.Run(tt.description, func(t *testing.T) {
+			var calls int64
+			count := func([]string) error {
+				atomic.AddInt64(&calls, 1)
+				return nil
+			}
+
+			for _, k := range []int{0, -1} {
+				if err := tt.parallel(context.Background(), letters, 2, k, count); err != ErrInvalidShardCount {
+					t.Errorf("k = %d: err = %v; want = %v", k, err, ErrInvalidShardCount)
+				}
+			}
+			if calls != 0 {
+				t.Fatalf("calls = %d; want = 0", calls)
+			}
+
+			// At most one goroutine per tuple is started, so this doesn't
+			// allocate MaxInt32 Shards.
+			if err := tt.parallel(context.Background(), letters, 2, math.MaxInt32, count); err != nil {
+				t.Fatalf("err = %s", err)
+			}
+			if expected := int64(len(tt.generate(letters, 2))); calls != expected {
+				t.Errorf("calls = %d; want = %d", calls, expected)
+			}
+		})
+	}
+
+	err := SlicePermutationsRParallel(context.Background(), letters, 6, 8, func([]string) error {
+		t.Error("fn was called for an empty space")
+		return nil
+	})
+	if err != nil {
+		t.Errorf("err = %s", err)
+	}
+}
+
+func TestParallelFirstError(t *testing.T) {
+	errBoom := errors.New("boom")
+	err := SliceCombinationsRParallel(context.Background(), letters, 2, 3, func(x []string) error {
+		if x[0] == "a" {
+			return errBoom
+		}
+		return nil
+	})
+	if err != errBoom {
+		t.Errorf("err = %v; want = %v", err, errBoom)
+	}
+}
+
+func TestParallelCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := SlicePermutationsRParallel(ctx, letters, 5, 2, func([]string) error {
+		called = true
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("err = %v; want = %v", err, context.Canceled)
+	}
+	if called {
+		t.Error("fn was called after cancellation")
+	}
+}
@@ -18,13 +18,68 @@ package stringz
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // ErrInconsistentUnpackLen is returned when Unpack is provided two slices
 // without the same length.
 var ErrInconsistentUnpackLen = errors.New("the length of the unpacked is not equal to the provided input")
 
+// maxUnpackLenErrorInput is the number of bytes of input retained by an
+// UnpackLenError.
+const maxUnpackLenErrorInput = 64
+
+// UnpackLenError is returned when the number of segments being unpacked
+// doesn't match the number of variables. It matches ErrInconsistentUnpackLen
+// with errors.Is.
+type UnpackLenError struct {
+	// Expected is the number of segments required.
+	Expected int
+	// Actual is the number of segments provided.
+	Actual int
+	// AtLeast is true if Expected is a minimum rather than an exact count.
+	AtLeast bool
+	// AtMost, if AtLeast is true and AtMost is positive, is the maximum
+	// number of segments accepted.
+	AtMost int
+	// Sep and Input are the separator and input that were split, if any.
+	// Input is truncated to a bounded length, ending in "...".
+	Sep   string
+	Input string
+}
+
+func newUnpackLenError(expected, actual int, atLeast bool, s, sep string) *UnpackLenError {
+	if len(s) > maxUnpackLenErrorInput {
+		cut := maxUnpackLenErrorInput
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut] + "..."
+	}
+	return &UnpackLenError{Expected: expected, Actual: actual, AtLeast: atLeast, Sep: sep, Input: s}
+}
+
+func (e *UnpackLenError) Error() string {
+	expected := strconv.Itoa(e.Expected)
+	switch {
+	case e.AtLeast && e.AtMost > 0:
+		expected = fmt.Sprintf("between %d and %d", e.Expected, e.AtMost)
+	case e.AtLeast:
+		expected = "at least " + expected
+	}
+	msg := fmt.Sprintf("%s: expected %s segments, got %d", ErrInconsistentUnpackLen, expected, e.Actual)
+	if e.Sep != "" || e.Input != "" {
+		msg += fmt.Sprintf(" splitting %q on %q", e.Input, e.Sep)
+	}
+	return msg
+}
+
+// Is reports whether target is ErrInconsistentUnpackLen.
+func (e *UnpackLenError) Is(target error) bool { return target == ErrInconsistentUnpackLen }
+
 // SliceContains returns true if the provided string is in the provided string
 // slice.
 func SliceContains(ys []string, x string) bool {
@@ -164,9 +219,11 @@ func CopyStringMap(xs map[string]string) map[string]string {
 }
 
 // Unpack assigns a slice into local variables.
+//
+// Returns an *UnpackLenError if the lengths differ.
 func Unpack(xs []string, vars ...*string) error {
 	if len(xs) != len(vars) {
-		return ErrInconsistentUnpackLen
+		return newUnpackLenError(len(vars), len(xs), false, "", "")
 	}
 	for i, x := range xs {
 		*vars[i] = x
@@ -177,10 +234,13 @@ func Unpack(xs []string, vars ...*string) error {
 // SplitExact splits the string `s` into `len(vars)` number of strings and
 // unpacks them into those vars.
 //
-// Returns ErrInconsistentUnpackLen if len(vars) doesn't match the number of
-// split segments.
+// Returns an *UnpackLenError if len(vars) doesn't match the number of split
+// segments.
 func SplitExact(s, sep string, vars ...*string) error {
 	exploded := strings.Split(s, sep)
+	if len(exploded) != len(vars) {
+		return newUnpackLenError(len(vars), len(exploded), false, s, sep)
+	}
 	return Unpack(exploded, vars...)
 }
 
@@ -188,13 +248,115 @@ func SplitExact(s, sep string, vars ...*string) error {
 // unpacks them into those vars. If there are more substrings that would be
 // split after len(vars), they will be all be put into the final variable.
 //
-// Returns ErrInconsistentUnpackLen if len(vars) is greater than the number
-// of split substrings.
+// Returns an *UnpackLenError if len(vars) is greater than the number of split
+// substrings.
 func SplitInto(s, sep string, vars ...*string) error {
 	exploded := strings.SplitN(s, sep, len(vars))
+	if len(exploded) != len(vars) {
+		return newUnpackLenError(len(vars), len(exploded), true, s, sep)
+	}
 	return Unpack(exploded, vars...)
 }
 
+// SplitIntoLast splits the string `s` into `len(vars)` number of strings and
+// unpacks them into those vars, working from the right like LastCut. If there
+// are more substrings that would be split before the last len(vars)-1, they
+// will all be put into the first variable.
+//
+// Returns an *UnpackLenError if len(vars) is greater than the number of split
+// substrings.
+func SplitIntoLast(s, sep string, vars ...*string) error {
+	exploded := SplitNLast(s, sep, len(vars))
+	if len(exploded) != len(vars) {
+		return newUnpackLenError(len(vars), len(exploded), true, s, sep)
+	}
+	return Unpack(exploded, vars...)
+}
+
+// SplitNLast is strings.SplitN, but anchored to the right: when there are
+// more than n substrings, the surplus is left unsplit in the first one rather
+// than the last.
+//
+//	SplitNLast("a.b.c.d", ".", 3) // []string{"a.b", "c", "d"}
+func SplitNLast(s, sep string, n int) []string {
+	if n == 0 {
+		return nil
+	}
+	exploded := strings.Split(s, sep)
+	if n < 0 || len(exploded) <= n {
+		return exploded
+	}
+	surplus := len(exploded) - n + 1
+	return append([]string{strings.Join(exploded[:surplus], sep)}, exploded[surplus:]...)
+}
+
+// SplitExactDefault is SplitExact, but only the first `required` variables
+// must have a segment. Each remaining variable is optional and is assigned
+// its value in fallbacks, with the semantics of DefaultEmpty, when its
+// segment is missing or empty.
+//
+//	var host, port, proto string
+//	SplitExactDefault("example.com", ":", 1, []string{"80", "tcp"}, &host, &port, &proto)
+//	// host, port, proto = "example.com", "80", "tcp"
+//
+// Returns an *UnpackLenError reporting the accepted range if there are fewer
+// than `required` or more than len(vars) segments, and an error if
+// len(fallbacks) isn't len(vars)-required.
+func SplitExactDefault(s, sep string, required int, fallbacks []string, vars ...*string) error {
+	if err := checkFallbacks(required, fallbacks, vars); err != nil {
+		return err
+	}
+	exploded := strings.Split(s, sep)
+	if len(exploded) < required || len(exploded) > len(vars) {
+		if required == len(vars) {
+			return newUnpackLenError(required, len(exploded), false, s, sep)
+		}
+		err := newUnpackLenError(required, len(exploded), true, s, sep)
+		err.AtMost = len(vars)
+		return err
+	}
+	return unpackDefault(exploded, s, sep, required, fallbacks, vars)
+}
+
+// SplitIntoDefault is SplitInto, but only the first `required` variables
+// must have a segment. Each remaining variable is optional and is assigned
+// its value in fallbacks, with the semantics of DefaultEmpty, when its
+// segment is missing or empty.
+//
+// Returns an *UnpackLenError if there are fewer than `required` segments and
+// an error if len(fallbacks) isn't len(vars)-required.
+func SplitIntoDefault(s, sep string, required int, fallbacks []string, vars ...*string) error {
+	if err := checkFallbacks(required, fallbacks, vars); err != nil {
+		return err
+	}
+	exploded := strings.SplitN(s, sep, len(vars))
+	return unpackDefault(exploded, s, sep, required, fallbacks, vars)
+}
+
+func checkFallbacks(required int, fallbacks []string, vars []*string) error {
+	if required < 0 || required > len(vars) || len(fallbacks) != len(vars)-required {
+		return fmt.Errorf("%d fallbacks provided for %d variables with %d required", len(fallbacks), len(vars), required)
+	}
+	return nil
+}
+
+func unpackDefault(exploded []string, s, sep string, required int, fallbacks []string, vars []*string) error {
+	if len(exploded) < required {
+		return newUnpackLenError(required, len(exploded), true, s, sep)
+	}
+	for i, v := range vars {
+		var segment string
+		if i < len(exploded) {
+			segment = exploded[i]
+		}
+		if i >= required {
+			segment = DefaultEmpty(segment, fallbacks[i-required])
+		}
+		*v = segment
+	}
+	return nil
+}
+
 // SlicePermutations returns all permutations of a string slice.
 //
 // It is equivalent to `SliceCombinationsR(xs, len(xs))`.
@@ -206,6 +368,7 @@ func SlicePermutations(xs []string) [][]string {
 // the provided string slice.
 //
 // If r is less than 0 or larger than the length of the pool, nil is returned.
+// If r is 0, a single empty tuple is returned.
 //
 // The permutation tuples are emitted in lexicographic ordering according to
 // the order of the input iterable. So, if the input iterable is sorted, the
@@ -215,66 +378,17 @@ func SlicePermutations(xs []string) [][]string {
 // So if the input elements are unique, there will be no repeat values in each
 // permutation.
 //
-// This is the algorithm used in Python's itertools library:
+// This matches the behavior of Python's itertools library:
 // itertools.permutations(iterable, r=None)
 func SlicePermutationsR(pool []string, r int) [][]string {
-	if r <= 0 || pool == nil || r > len(pool) {
-		return nil
-	}
-	n := len(pool)
-
-	indices := make([]int, n)
-	for i := range pool {
-		indices[i] = i
-	}
-
-	var ys [][]string
-
-	{
-		var y []string
-		for _, i := range indices[:r] {
-			y = append(y, pool[i])
-		}
-		ys = append(ys, y)
-	}
-
-	cycles := make([]int, n-(n-r))
-	for i := range cycles {
-		cycles[i] = n - i
-	}
-
-	for {
-		broke := false
-
-		for i := r - 1; i >= 0; i-- {
-			cycles[i] = cycles[i] - 1
-			if cycles[i] == 0 {
-				indices = append(indices[:i], append(indices[i+1:], indices[i:i+1]...)...)
-				cycles[i] = n - i
-			} else {
-				j := cycles[i]
-				indices[i], indices[len(indices)-j] = indices[len(indices)-j], indices[i]
-
-				var y []string
-				for _, i := range indices[:r] {
-					y = append(y, pool[i])
-				}
-				ys = append(ys, y)
-
-				broke = true
-				break
-			}
-		}
-		if !broke {
-			return ys
-		}
-	}
+	return PermutationsR(pool, r)
 }
 
 // SliceCombinationsR returns r-length subsequences of elements from the
 // provided string slice.
 //
 // If r is less than 0 or larger than the length of the pool, nil is returned.
+// If r is 0, a single empty tuple is returned.
 //
 // The combinations are emitted in lexicographic ordering according to the
 // order of the input iterable. So, if the input iterable is sorted, the
@@ -284,60 +398,19 @@ func SlicePermutationsR(pool []string, r int) [][]string {
 // So if the input elements are unique, there will be no repeat values in each
 // combination.
 //
-// This is the algorithm used in Python's itertools library:
+// This matches the behavior of Python's itertools library:
 // itertools.combinations(iterable, r)
 func SliceCombinationsR(pool []string, r int) [][]string {
-	if r <= 0 || pool == nil || r > len(pool) {
-		return nil
-	}
-	n := len(pool)
-
-	indices := make([]int, r)
-	for i := range indices {
-		indices[i] = i
-	}
-
-	var ys [][]string
-
-	{
-		var y []string
-		for _, j := range indices {
-			y = append(y, pool[j])
-		}
-		ys = append(ys, y)
-	}
-
-	for {
-		var i int
-		broke := false
-		for i = r - 1; i >= 0; i-- {
-			if indices[i] != i+n-r {
-				broke = true
-				break
-			}
-		}
-		if !broke {
-			return ys
-		}
-
-		indices[i] = indices[i] + 1
-		for j := i + 1; j < r; j++ {
-			indices[j] = indices[j-1] + 1
-		}
-
-		var y []string
-		for _, j := range indices {
-			y = append(y, pool[j])
-		}
-		ys = append(ys, y)
-	}
+	return CombinationsR(pool, r)
 }
 
 // SliceCombinationsWithReplacement returns r-length subsequences of elements
 // from the provided string slice allowing individual elements to be repeated
 // more than once.
 //
-// If r is less than 0 or larger than the length of the pool, nil is returned.
+// If r is less than 0, or the pool is empty and r is larger than 0, nil is
+// returned. If r is 0, a single empty tuple is returned. Because elements may
+// be repeated, r may be larger than the length of the pool.
 //
 // The combination tuples are emitted in lexicographic ordering according to
 // the order of the input iterable. So, if the input iterable is sorted, the
@@ -347,49 +420,69 @@ func SliceCombinationsR(pool []string, r int) [][]string {
 // So if the input elements are unique, the generated combinations will also be
 // unique.
 //
-// This is the algorithm used in Python's itertools library:
+// This matches the behavior of Python's itertools library:
 // itertools.combinations_with_replacement(iterable, r)
 func SliceCombinationsWithReplacement(pool []string, r int) [][]string {
-	if r <= 0 || pool == nil || r > len(pool) {
-		return nil
-	}
-	n := len(pool)
+	return CombinationsWithReplacement(pool, r)
+}
 
-	indices := make([]int, r)
-	var ys [][]string
+// NextPermutation rearranges xs in place into the next permutation of its
+// values in lexicographic order and returns true.
+//
+// If xs is already the last permutation, it is rearranged into the first,
+// sorted permutation and false is returned. Duplicate values are handled, so
+// stepping from the sorted permutation visits each distinct permutation
+// exactly once.
+//
+// This is the behavior of C++'s std::next_permutation.
+func NextPermutation(xs []string) bool {
+	i := len(xs) - 2
+	for i >= 0 && xs[i] >= xs[i+1] {
+		i--
+	}
+	if i < 0 {
+		reverse(xs)
+		return false
+	}
 
-	{
-		var y []string
-		for _, j := range indices {
-			y = append(y, pool[j])
-		}
-		ys = append(ys, y)
+	j := len(xs) - 1
+	for xs[j] <= xs[i] {
+		j--
 	}
+	xs[i], xs[j] = xs[j], xs[i]
+	reverse(xs[i+1:])
+	return true
+}
 
-	for {
-		var i int
-		broke := false
-		for i = r - 1; i >= 0; i-- {
-			if indices[i] != n-1 {
-				broke = true
-				break
-			}
-		}
-		if !broke {
-			return ys
-		}
+// PrevPermutation rearranges xs in place into the previous permutation of
+// its values in lexicographic order and returns true.
+//
+// If xs is already the first, sorted permutation, it is rearranged into the
+// last permutation and false is returned.
+//
+// This is the behavior of C++'s std::prev_permutation.
+func PrevPermutation(xs []string) bool {
+	i := len(xs) - 2
+	for i >= 0 && xs[i] <= xs[i+1] {
+		i--
+	}
+	if i < 0 {
+		reverse(xs)
+		return false
+	}
 
-		newIndices := make([]int, r-i)
-		for j := range newIndices {
-			newIndices[j] = indices[i] + 1
-		}
-		indices = append(indices[:i], newIndices...)
+	j := len(xs) - 1
+	for xs[j] >= xs[i] {
+		j--
+	}
+	xs[i], xs[j] = xs[j], xs[i]
+	reverse(xs[i+1:])
+	return true
+}
 
-		var y []string
-		for _, j := range indices {
-			y = append(y, pool[j])
-		}
-		ys = append(ys, y)
+func reverse(xs []string) {
+	for i, j := 0, len(xs)-1; i < j; i, j = i+1, j-1 {
+		xs[i], xs[j] = xs[j], xs[i]
 	}
 }
 
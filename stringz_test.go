@@ -15,6 +15,9 @@
 package stringz
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -214,9 +217,9 @@ func TestSlicePermutationsR(t *testing.T) {
 	}{
 		{"r is negative", []string{"a", "b"}, -1, nil},
 		{"r > len(xs)", []string{"a", "b"}, 10, nil},
-		{"nil slice", nil, 0, nil},
-		{"empty slice", []string{}, 0, nil},
-		{"two items r = 0", []string{"a", "b"}, 0, nil},
+		{"nil slice", nil, 0, [][]string{{}}},
+		{"empty slice", []string{}, 0, [][]string{{}}},
+		{"two items r = 0", []string{"a", "b"}, 0, [][]string{{}}},
 		{"two items r = 1", []string{"a", "b"}, 1, [][]string{{"a"}, {"b"}}},
 		{"two items r = 2", []string{"a", "b"}, 2, [][]string{{"a", "b"}, {"b", "a"}}},
 		{"duplicates", []string{"a", "a"}, 2, [][]string{{"a", "a"}, {"a", "a"}}},
@@ -249,9 +252,9 @@ func TestSliceCombinationsR(t *testing.T) {
 	}{
 		{"r is negative", []string{"a", "b"}, -1, nil},
 		{"r > len(xs)", []string{"a", "b"}, 10, nil},
-		{"nil slice", nil, 0, nil},
-		{"empty slice", []string{}, 0, nil},
-		{"two items r = 0", []string{"a", "b"}, 0, nil},
+		{"nil slice", nil, 0, [][]string{{}}},
+		{"empty slice", []string{}, 0, [][]string{{}}},
+		{"two items r = 0", []string{"a", "b"}, 0, [][]string{{}}},
 		{"two items r = 1", []string{"a", "b"}, 1, [][]string{{"a"}, {"b"}}},
 		{"two items r = 2", []string{"a", "b"}, 2, [][]string{{"a", "b"}}},
 		{"duplicates", []string{"a", "a"}, 2, [][]string{{"a", "a"}}},
@@ -276,10 +279,11 @@ func TestSliceCombinationsWithReplacement(t *testing.T) {
 		expected    [][]string
 	}{
 		{"r is negative", []string{"a", "b"}, -1, nil},
-		{"r > len(xs)", []string{"a", "b"}, 10, nil},
-		{"nil slice", nil, 0, nil},
-		{"empty slice", []string{}, 0, nil},
-		{"two items r = 0", []string{"a", "b"}, 0, nil},
+		{"r > len(xs)", []string{"a", "b"}, 3, [][]string{{"a", "a", "a"}, {"a", "a", "b"}, {"a", "b", "b"}, {"b", "b", "b"}}},
+		{"nil slice", nil, 0, [][]string{{}}},
+		{"empty slice", []string{}, 0, [][]string{{}}},
+		{"empty slice r = 1", []string{}, 1, nil},
+		{"two items r = 0", []string{"a", "b"}, 0, [][]string{{}}},
 		{"two items r = 1", []string{"a", "b"}, 1, [][]string{{"a"}, {"b"}}},
 		{"two items r = 2", []string{"a", "b"}, 2, [][]string{{"a", "a"}, {"a", "b"}, {"b", "b"}}},
 		{"duplicates", []string{"a", "a"}, 2, [][]string{{"a", "a"}, {"a", "a"}, {"a", "a"}}},
@@ -307,6 +311,77 @@ func TestSliceCombinationsWithReplacement(t *testing.T) {
 	}
 }
 
+func TestNextPermutation(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		expected    []string
+		more        bool
+	}{
+		{"nil slice", nil, nil, false},
+		{"single item", []string{"a"}, []string{"a"}, false},
+		{"first", []string{"a", "b", "c"}, []string{"a", "c", "b"}, true},
+		{"middle", []string{"b", "c", "a"}, []string{"c", "a", "b"}, true},
+		{"wraps around", []string{"c", "b", "a"}, []string{"a", "b", "c"}, false},
+		{"duplicates", []string{"a", "b", "a"}, []string{"b", "a", "a"}, true},
+		{"all duplicates", []string{"a", "a"}, []string{"a", "a"}, false},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			more := NextPermutation(tt.xs)
+			if more != tt.more {
+				t.Errorf("more = %v; want = %v", more, tt.more)
+			}
+			if !SliceEqual(tt.xs, tt.expected) {
+				t.Errorf("actual = %v; want = %v", tt.xs, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrevPermutation(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		expected    []string
+		more        bool
+	}{
+		{"nil slice", nil, nil, false},
+		{"single item", []string{"a"}, []string{"a"}, false},
+		{"last", []string{"c", "b", "a"}, []string{"c", "a", "b"}, true},
+		{"middle", []string{"c", "a", "b"}, []string{"b", "c", "a"}, true},
+		{"wraps around", []string{"a", "b", "c"}, []string{"c", "b", "a"}, false},
+		{"duplicates", []string{"b", "a", "a"}, []string{"a", "b", "a"}, true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			more := PrevPermutation(tt.xs)
+			if more != tt.more {
+				t.Errorf("more = %v; want = %v", more, tt.more)
+			}
+			if !SliceEqual(tt.xs, tt.expected) {
+				t.Errorf("actual = %v; want = %v", tt.xs, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNextPermutationMatchesMultiset(t *testing.T) {
+	xs := []string{"a", "a", "b", "b", "c"}
+
+	var actual [][]string
+	for more := true; more; more = NextPermutation(xs) {
+		actual = append(actual, append([]string{}, xs...))
+	}
+
+	expected := SliceMultisetPermutationsR(xs, len(xs))
+	if !MatrixEqual(actual, expected) {
+		t.Errorf("actual = %v; want = %v", actual, expected)
+	}
+}
+
 func TestSplitExact(t *testing.T) {
 	testCases := []struct {
 		src         string
@@ -329,7 +404,7 @@ func TestSplitExact(t *testing.T) {
 			}
 
 			err := SplitExact(tc.src, "/", destVars...)
-			if err != tc.expectedErr {
+			if !errors.Is(err, tc.expectedErr) {
 				t.Fatalf("actual = %s, want = %s", err, tc.expectedErr)
 			}
 			if err == nil {
@@ -365,7 +440,44 @@ func TestSplitInto(t *testing.T) {
 			}
 
 			err := SplitInto(tc.src, "/", destVars...)
-			if err != tc.expectedErr {
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("actual = %s, want = %s", err, tc.expectedErr)
+			}
+			if err == nil {
+				for i := range tc.parts {
+					if tc.parts[i] != dests[i] {
+						t.Fatalf("actual[%d] = %s, expected[%d] = %s", i, dests[i], i, tc.parts[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSplitIntoLast(t *testing.T) {
+	testCases := []struct {
+		src         string
+		expectedErr error
+		parts       []string
+	}{
+		{"", nil, []string{""}},
+		{"one/two", nil, []string{"one", "two"}},
+		{"one/two/three/four/five", nil, []string{"one/two/three/four", "five"}},
+		{"a.b/c/d", nil, []string{"a.b", "c", "d"}},
+		{"one/two", nil, []string{"one/two"}},
+		{"one", ErrInconsistentUnpackLen, []string{"one", "two"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.src, func(t *testing.T) {
+			dests := make([]string, len(tc.parts))
+			destVars := make([]*string, len(tc.parts))
+
+			for i := range dests {
+				destVars[i] = &dests[i]
+			}
+
+			err := SplitIntoLast(tc.src, "/", destVars...)
+			if !errors.Is(err, tc.expectedErr) {
 				t.Fatalf("actual = %s, want = %s", err, tc.expectedErr)
 			}
 			if err == nil {
@@ -378,3 +490,175 @@ func TestSplitInto(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitNLast(t *testing.T) {
+	table := []struct {
+		s        string
+		sep      string
+		n        int
+		expected []string
+	}{
+		{"a.b.c.d", ".", 3, []string{"a.b", "c", "d"}},
+		{"a.b.c.d", ".", 1, []string{"a.b.c.d"}},
+		{"a.b.c.d", ".", 4, []string{"a", "b", "c", "d"}},
+		{"a.b.c.d", ".", 9, []string{"a", "b", "c", "d"}},
+		{"a.b.c.d", ".", -1, []string{"a", "b", "c", "d"}},
+		{"a.b.c.d", ".", 0, nil},
+		{"a::b::c", "::", 2, []string{"a::b", "c"}},
+		{"abc", "", 2, []string{"ab", "c"}},
+		{"", ".", 2, []string{""}},
+	}
+
+	for _, tt := range table {
+		t.Run(fmt.Sprintf("%s/%d", tt.s, tt.n), func(t *testing.T) {
+			actual := SplitNLast(tt.s, tt.sep, tt.n)
+			if fmt.Sprintf("%q", actual) != fmt.Sprintf("%q", tt.expected) {
+				t.Errorf("actual = %q; want = %q", actual, tt.expected)
+			}
+			if tt.n <= 1 || len(strings.Split(tt.s, tt.sep)) <= tt.n {
+				if expected := strings.SplitN(tt.s, tt.sep, tt.n); fmt.Sprintf("%q", actual) != fmt.Sprintf("%q", expected) {
+					t.Errorf("actual = %q; want strings.SplitN = %q", actual, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestUnpackLenError(t *testing.T) {
+	var a, b, c string
+	long := strings.Repeat("é", 40)
+	table := []struct {
+		description string
+		err         error
+		expected    UnpackLenError
+	}{
+		{"unpack", Unpack([]string{"x"}, &a, &b), UnpackLenError{Expected: 2, Actual: 1}},
+		{"exact too few", SplitExact("x/y", "/", &a, &b, &c), UnpackLenError{Expected: 3, Actual: 2, Sep: "/", Input: "x/y"}},
+		{"exact too many", SplitExact("x/y/z", "/", &a, &b), UnpackLenError{Expected: 2, Actual: 3, Sep: "/", Input: "x/y/z"}},
+		{"into", SplitInto("x", "/", &a, &b), UnpackLenError{Expected: 2, Actual: 1, AtLeast: true, Sep: "/", Input: "x"}},
+		{"truncated", SplitExact(long, "/", &a, &b), UnpackLenError{Expected: 2, Actual: 1, Sep: "/", Input: strings.Repeat("é", 32) + "..."}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			if !errors.Is(tt.err, ErrInconsistentUnpackLen) {
+				t.Errorf("errors.Is(%v, ErrInconsistentUnpackLen) = false", tt.err)
+			}
+			var lenErr *UnpackLenError
+			if !errors.As(tt.err, &lenErr) {
+				t.Fatalf("err = %v; want = *UnpackLenError", tt.err)
+			}
+			if *lenErr != tt.expected {
+				t.Errorf("actual = %+v; want = %+v", *lenErr, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitExactDefault(t *testing.T) {
+	fallbacks := []string{"80", "tcp"}
+	table := []struct {
+		src         string
+		expectedErr error
+		parts       []string
+	}{
+		{"example.com", nil, []string{"example.com", "80", "tcp"}},
+		{"example.com:443", nil, []string{"example.com", "443", "tcp"}},
+		{"example.com:443:udp", nil, []string{"example.com", "443", "udp"}},
+		{"example.com::udp", nil, []string{"example.com", "80", "udp"}},
+		{"example.com:", nil, []string{"example.com", "80", "tcp"}},
+		{"", nil, []string{"", "80", "tcp"}},
+		{"example.com:443:udp:extra", ErrInconsistentUnpackLen, nil},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.src, func(t *testing.T) {
+			var host, port, proto string
+			err := SplitExactDefault(tt.src, ":", 1, fallbacks, &host, &port, &proto)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("actual = %v; want = %v", err, tt.expectedErr)
+			}
+			if err == nil && !SliceEqual([]string{host, port, proto}, tt.parts) {
+				t.Errorf("actual = %q; want = %q", []string{host, port, proto}, tt.parts)
+			}
+		})
+	}
+
+	var host, port, proto string
+	lenErrors := []struct {
+		description string
+		err         error
+		expected    UnpackLenError
+		msg         string
+	}{
+		{
+			"too many",
+			SplitExactDefault("a:1:tcp:x", ":", 1, fallbacks, &host, &port, &proto),
+			UnpackLenError{Expected: 1, Actual: 4, AtLeast: true, AtMost: 3, Sep: ":", Input: "a:1:tcp:x"},
+			"expected between 1 and 3 segments, got 4",
+		},
+		{
+			"too few",
+			SplitExactDefault("a", ":", 2, []string{"tcp"}, &host, &port, &proto),
+			UnpackLenError{Expected: 2, Actual: 1, AtLeast: true, AtMost: 3, Sep: ":", Input: "a"},
+			"expected between 2 and 3 segments, got 1",
+		},
+		{
+			"all required",
+			SplitExactDefault("example.com", ":", 2, nil, &host, &port),
+			UnpackLenError{Expected: 2, Actual: 1, Sep: ":", Input: "example.com"},
+			"expected 2 segments, got 1",
+		},
+	}
+	for _, tt := range lenErrors {
+		t.Run(tt.description, func(t *testing.T) {
+			var lenErr *UnpackLenError
+			if !errors.As(tt.err, &lenErr) {
+				t.Fatalf("err = %v; want = *UnpackLenError", tt.err)
+			}
+			if *lenErr != tt.expected {
+				t.Errorf("actual = %+v; want = %+v", *lenErr, tt.expected)
+			}
+			if !strings.Contains(lenErr.Error(), tt.msg) {
+				t.Errorf("Error() = %q; want it to contain %q", lenErr.Error(), tt.msg)
+			}
+		})
+	}
+
+	for _, required := range []int{-1, 0, 3} {
+		if err := SplitExactDefault("a", ":", required, []string{"x"}, &host, &port); err == nil || errors.Is(err, ErrInconsistentUnpackLen) {
+			t.Errorf("required = %d: err = %v; want fallback count error", required, err)
+		}
+	}
+}
+
+func TestSplitIntoDefault(t *testing.T) {
+	table := []struct {
+		src         string
+		expectedErr error
+		parts       []string
+	}{
+		{"one", nil, []string{"one", "two", "three"}},
+		{"one/2", nil, []string{"one", "2", "three"}},
+		{"one/2/3/4", nil, []string{"one", "2", "3/4"}},
+		{"one//", nil, []string{"one", "two", "three"}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.src, func(t *testing.T) {
+			var a, b, c string
+			err := SplitIntoDefault(tt.src, "/", 1, []string{"two", "three"}, &a, &b, &c)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("actual = %v; want = %v", err, tt.expectedErr)
+			}
+			if err == nil && !SliceEqual([]string{a, b, c}, tt.parts) {
+				t.Errorf("actual = %q; want = %q", []string{a, b, c}, tt.parts)
+			}
+		})
+	}
+
+	var a, b string
+	if err := SplitIntoDefault("one", "/", 2, nil, &a, &b); !errors.Is(err, ErrInconsistentUnpackLen) {
+		t.Errorf("err = %v; want = %v", err, ErrInconsistentUnpackLen)
+	}
+}
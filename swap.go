@@ -0,0 +1,112 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+// SwapIterator lazily produces every permutation of a string slice, where
+// each permutation differs from the one before it by a single swap of two
+// adjacent elements.
+//
+// It is used the same way as an Iterator, but it rearranges a single slice in
+// place rather than allocating a new slice for every permutation.
+type SwapIterator struct {
+	value     []string
+	labels    []int
+	dirs      []int
+	swapped   [2]int
+	started   bool
+	exhausted bool
+}
+
+// SliceMinimalChangePermutations returns a SwapIterator over every
+// permutation of the provided string slice.
+//
+// The permutations are emitted in the order of the Steinhaus-Johnson-Trotter
+// algorithm, starting with the pool itself. Callers that cache state derived
+// from a permutation can use Swapped to update it rather than recomputing it.
+//
+// Elements are treated as unique based on their position, not on their value.
+// So if the input elements are unique, there will be no repeat values in each
+// permutation.
+func SliceMinimalChangePermutations(pool []string) *SwapIterator {
+	it := &SwapIterator{
+		value:   append([]string{}, pool...),
+		labels:  make([]int, len(pool)),
+		dirs:    make([]int, len(pool)),
+		swapped: [2]int{-1, -1},
+	}
+	for i := range it.labels {
+		it.labels[i] = i
+		it.dirs[i] = -1
+	}
+	return it
+}
+
+// Next advances the SwapIterator to its next permutation. It returns false
+// once every permutation has been produced.
+func (it *SwapIterator) Next() bool {
+	if it.exhausted {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		return true
+	}
+
+	// Find the largest label that points at a smaller adjacent label.
+	mobile := -1
+	for i, label := range it.labels {
+		j := i + it.dirs[i]
+		if j < 0 || j >= len(it.labels) || it.labels[j] > label {
+			continue
+		}
+		if mobile < 0 || label > it.labels[mobile] {
+			mobile = i
+		}
+	}
+	if mobile < 0 {
+		it.exhausted = true
+		it.value, it.swapped = nil, [2]int{-1, -1}
+		return false
+	}
+
+	label := it.labels[mobile]
+	j := mobile + it.dirs[mobile]
+	it.labels[mobile], it.labels[j] = it.labels[j], it.labels[mobile]
+	it.dirs[mobile], it.dirs[j] = it.dirs[j], it.dirs[mobile]
+	it.value[mobile], it.value[j] = it.value[j], it.value[mobile]
+	it.swapped = [2]int{mobile, j}
+	if mobile > j {
+		it.swapped = [2]int{j, mobile}
+	}
+
+	// Every label larger than the one that moved changes direction.
+	for i, other := range it.labels {
+		if other > label {
+			it.dirs[i] = -it.dirs[i]
+		}
+	}
+	return true
+}
+
+// Value returns the permutation produced by the most recent call to Next.
+//
+// The same slice is rearranged by every call to Next, so it must not be
+// modified and must be copied in order to be retained.
+func (it *SwapIterator) Value() []string { return it.value }
+
+// Swapped returns the two indices, in increasing order, that were swapped to
+// produce the current permutation from the previous one. For the first
+// permutation, both indices are -1.
+func (it *SwapIterator) Swapped() (i, j int) { return it.swapped[0], it.swapped[1] }
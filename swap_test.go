@@ -0,0 +1,88 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSliceMinimalChangePermutations(t *testing.T) {
+	table := []struct {
+		description string
+		xs          []string
+		expected    [][]string
+	}{
+		{"nil slice", nil, [][]string{{}}},
+		{"single item", []string{"a"}, [][]string{{"a"}}},
+		{"common case", []string{"a", "b", "c"}, [][]string{
+			{"a", "b", "c"},
+			{"a", "c", "b"},
+			{"c", "a", "b"},
+			{"c", "b", "a"},
+			{"b", "c", "a"},
+			{"b", "a", "c"},
+		}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			var actual [][]string
+			for it := SliceMinimalChangePermutations(tt.xs); it.Next(); {
+				actual = append(actual, append([]string{}, it.Value()...))
+			}
+			if !MatrixEqual(actual, tt.expected) {
+				t.Errorf("actual = %v; want = %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSliceMinimalChangePermutationsSwapped(t *testing.T) {
+	it := SliceMinimalChangePermutations(letters)
+
+	var (
+		previous []string
+		seen     []string
+	)
+	for it.Next() {
+		i, j := it.Swapped()
+		if previous == nil {
+			if i != -1 || j != -1 {
+				t.Fatalf("first Swapped() = %d, %d; want = -1, -1", i, j)
+			}
+		} else {
+			if j != i+1 {
+				t.Fatalf("Swapped() = %d, %d; want adjacent indices", i, j)
+			}
+			previous[i], previous[j] = previous[j], previous[i]
+			if !SliceEqual(previous, it.Value()) {
+				t.Fatalf("swapping %d and %d gives %v; want = %v", i, j, previous, it.Value())
+			}
+		}
+		previous = append([]string{}, it.Value()...)
+		seen = append(seen, strings.Join(previous, ","))
+	}
+
+	expected := make([]string, 0, len(seen))
+	for _, p := range SlicePermutations(letters) {
+		expected = append(expected, strings.Join(p, ","))
+	}
+	sort.Strings(seen)
+	if !SliceEqual(seen, expected) {
+		t.Errorf("did not produce every permutation exactly once")
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnpackError is returned when a segment cannot be converted into the type
+// of the variable it is being unpacked into.
+type UnpackError struct {
+	// Field is the name of the struct field being unpacked into, if any.
+	Field   string
+	Index   int
+	Segment string
+	Err     error
+}
+
+func (e *UnpackError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("field %s: segment %d (%q): %s", e.Field, e.Index, e.Segment, e.Err)
+	}
+	return fmt.Sprintf("segment %d (%q): %s", e.Index, e.Segment, e.Err)
+}
+
+// Unwrap returns the underlying conversion error.
+func (e *UnpackError) Unwrap() error { return e.Err }
+
+// UnpackAny is Unpack, but the variables may be pointers to any of the
+// following types, into which each segment is parsed:
+//
+//   - string
+//   - bool, parsed by strconv.ParseBool
+//   - int, int8, int16, int32, int64, parsed by strconv.ParseInt
+//   - uint, uint8, uint16, uint32, uint64, parsed by strconv.ParseUint
+//   - float32, float64, parsed by strconv.ParseFloat
+//   - time.Duration, parsed by time.ParseDuration
+//   - any type implementing encoding.TextUnmarshaler
+//
+// Types whose underlying type is a string, bool, integer or float kind are
+// also supported and parsed by that kind. Because the underlying type of
+// time.Duration is int64, a type defined as `type T time.Duration` is parsed
+// as an integer; implement encoding.TextUnmarshaler to parse it as a
+// duration.
+//
+// Returns an *UnpackLenError if the lengths differ and an *UnpackError
+// for the first segment that cannot be parsed. Variables preceding that
+// segment will have already been assigned.
+func UnpackAny(xs []string, vars ...any) error {
+	if len(xs) != len(vars) {
+		return newUnpackLenError(len(vars), len(xs), false, "", "")
+	}
+	for i, x := range xs {
+		if err := assign(vars[i], x); err != nil {
+			return &UnpackError{Index: i, Segment: x, Err: err}
+		}
+	}
+	return nil
+}
+
+// SplitExactAny is SplitExact, but unpacks with the semantics of UnpackAny.
+func SplitExactAny(s, sep string, vars ...any) error {
+	exploded := strings.Split(s, sep)
+	if len(exploded) != len(vars) {
+		return newUnpackLenError(len(vars), len(exploded), false, s, sep)
+	}
+	return UnpackAny(exploded, vars...)
+}
+
+// SplitIntoAny is SplitInto, but unpacks with the semantics of UnpackAny.
+func SplitIntoAny(s, sep string, vars ...any) error {
+	exploded := strings.SplitN(s, sep, len(vars))
+	if len(exploded) != len(vars) {
+		return newUnpackLenError(len(vars), len(exploded), true, s, sep)
+	}
+	return UnpackAny(exploded, vars...)
+}
+
+// SplitStruct splits the string `s` around each instance of sep and unpacks
+// the segments into the fields of the struct that dst points to.
+//
+// Fields are selected with a `stringz` struct tag:
+//
+//	Host  string        `stringz:"0"`
+//	Port  uint16        `stringz:"1,optional"`
+//	Flags []string      `stringz:"rest"`
+//
+// A numbered field receives the segment at that index, parsed with the
+// semantics of UnpackAny. The numbered fields must cover every index from 0
+// to the highest, so that no segment is silently dropped. If there is no such segment, it is an error unless
+// the field is optional, in which case the field is left untouched. A rest
+// field, which must be a string or a []string, receives every segment after
+// the highest numbered field; as a string, they are joined by sep. Fields
+// without a tag or tagged "-" are ignored.
+//
+// Returns an *UnpackError naming the field and segment that could not be
+// unpacked. A missing segment wraps an *UnpackLenError, and one is returned
+// directly for segments left over when there is no rest field.
+func SplitStruct(s, sep string, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot split into %T, which is not a non-nil pointer to a struct", dst)
+	}
+	v = v.Elem()
+
+	fields, rest, err := structFields(v.Type())
+	if err != nil {
+		return err
+	}
+
+	segments := strings.Split(s, sep)
+	next := 0
+	for _, f := range fields {
+		next = f.segment + 1
+		if f.segment >= len(segments) {
+			if f.optional {
+				continue
+			}
+			return &UnpackError{
+				Field: f.name,
+				Index: f.segment,
+				Err:   newUnpackLenError(f.segment+1, len(segments), true, s, sep),
+			}
+		}
+
+		segment := segments[f.segment]
+		if err := assignValue(v.Field(f.index), segment); err != nil {
+			return &UnpackError{Field: f.name, Index: f.segment, Segment: segment, Err: err}
+		}
+	}
+
+	var remaining []string
+	if next < len(segments) {
+		remaining = segments[next:]
+	}
+	if rest == nil {
+		if len(remaining) > 0 {
+			return newUnpackLenError(next, len(segments), false, s, sep)
+		}
+		return nil
+	}
+
+	if field := v.Field(rest.index); field.Kind() == reflect.String {
+		field.SetString(strings.Join(remaining, sep))
+	} else {
+		field.Set(reflect.ValueOf(remaining))
+	}
+	return nil
+}
+
+// structField is a field of a struct selected by a `stringz` tag.
+type structField struct {
+	name     string
+	index    int
+	segment  int
+	optional bool
+}
+
+// structFields returns the numbered fields of t ordered by segment, along
+// with its rest field, if any.
+func structFields(t reflect.Type) (fields []structField, rest *structField, err error) {
+	claimed := make(map[int]string)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("stringz")
+		if !ok || tag == "-" {
+			continue
+		}
+		invalid := func(reason string) error {
+			return fmt.Errorf("invalid stringz tag %q on field %s: %s", tag, sf.Name, reason)
+		}
+		if !sf.IsExported() {
+			return nil, nil, invalid("field is not exported")
+		}
+
+		if tag == "rest" {
+			if rest != nil {
+				return nil, nil, invalid("field " + rest.name + " is already the rest field")
+			}
+			if sf.Type.Kind() != reflect.String && sf.Type != reflect.TypeOf([]string(nil)) {
+				return nil, nil, invalid("rest field must be a string or a []string")
+			}
+			rest = &structField{name: sf.Name, index: i}
+			continue
+		}
+
+		index, option, _ := strings.Cut(tag, ",")
+		segment, err := strconv.Atoi(index)
+		if err != nil || segment < 0 {
+			return nil, nil, invalid("segment must be a non-negative integer or \"rest\"")
+		}
+		if option != "" && option != "optional" {
+			return nil, nil, invalid("unknown option " + strconv.Quote(option))
+		}
+		if other, ok := claimed[segment]; ok {
+			return nil, nil, invalid("segment is already unpacked into field " + other)
+		}
+		claimed[segment] = sf.Name
+
+		fields = append(fields, structField{name: sf.Name, index: i, segment: segment, optional: option == "optional"})
+	}
+
+	sort.Slice(fields, func(i, j int) bool { return fields[i].segment < fields[j].segment })
+	for i, f := range fields {
+		if f.segment != i {
+			sf := t.Field(f.index)
+			return nil, nil, fmt.Errorf("invalid stringz tag %q on field %s: segment %d is not unpacked into any field", sf.Tag.Get("stringz"), f.name, i)
+		}
+	}
+	return fields, rest, nil
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// assign parses s into the value that dst points to.
+func assign(dst any, s string) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("cannot unpack into %T, which is not a non-nil pointer", dst)
+	}
+	return assignValue(v.Elem(), s)
+}
+
+// assignValue parses s into v, which must be addressable.
+func assignValue(v reflect.Value, s string) error {
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot unpack into unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+// Copyright 2019 Jimmy Zelinskie
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stringz
+
+import (
+	"errors"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type port uint16
+
+type timeout time.Duration
+
+func TestUnpackAny(t *testing.T) {
+	var (
+		s   string
+		b   bool
+		i   int
+		i8  int8
+		u64 uint64
+		f   float64
+		d   time.Duration
+		p   port
+		ip  netip.Addr
+	)
+	err := UnpackAny(
+		[]string{"str", "true", "-42", "-8", "18446744073709551615", "2.5", "1m30s", "8080", "10.0.0.1"},
+		&s, &b, &i, &i8, &u64, &f, &d, &p, &ip,
+	)
+	if err != nil {
+		t.Fatalf("err = %s", err)
+	}
+
+	if s != "str" || !b || i != -42 || i8 != -8 || u64 != 18446744073709551615 || f != 2.5 || d != 90*time.Second || p != 8080 {
+		t.Errorf("unexpected values: %v %v %v %v %v %v %v %v", s, b, i, i8, u64, f, d, p)
+	}
+	if ip != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("ip = %s; want = 10.0.0.1", ip)
+	}
+}
+
+func TestUnpackAnyErrors(t *testing.T) {
+	var (
+		i  int
+		i8 int8
+		b  bool
+		d  time.Duration
+		ip netip.Addr
+		ch chan int
+	)
+	table := []struct {
+		description string
+		xs          []string
+		vars        []any
+		index       int
+		expectedErr error
+	}{
+		{"invalid int", []string{"1", "x"}, []any{&i, &i}, 1, strconv.ErrSyntax},
+		{"int8 overflow", []string{"128"}, []any{&i8}, 0, strconv.ErrRange},
+		{"invalid bool", []string{"maybe"}, []any{&b}, 0, strconv.ErrSyntax},
+		{"invalid duration", []string{"soon"}, []any{&d}, 0, nil},
+		{"invalid text", []string{"not-an-ip"}, []any{&ip}, 0, nil},
+		{"unsupported type", []string{"x"}, []any{&ch}, 0, nil},
+		{"not a pointer", []string{"x"}, []any{i}, 0, nil},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			err := UnpackAny(tt.xs, tt.vars...)
+			var unpackErr *UnpackError
+			if !errors.As(err, &unpackErr) {
+				t.Fatalf("err = %v; want = *UnpackError", err)
+			}
+			if unpackErr.Index != tt.index || unpackErr.Segment != tt.xs[tt.index] {
+				t.Errorf("Index, Segment = %d, %q; want = %d, %q", unpackErr.Index, unpackErr.Segment, tt.index, tt.xs[tt.index])
+			}
+			if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.expectedErr)
+			}
+		})
+	}
+
+	if err := UnpackAny([]string{"1"}, &i, &i); !errors.Is(err, ErrInconsistentUnpackLen) {
+		t.Errorf("err = %v; want = %v", err, ErrInconsistentUnpackLen)
+	}
+}
+
+func TestSplitAny(t *testing.T) {
+	var (
+		host string
+		p    port
+		rest string
+		ttl  time.Duration
+	)
+	if err := SplitExactAny("example.com:443:5s", ":", &host, &p, &ttl); err != nil {
+		t.Fatalf("err = %s", err)
+	}
+	if host != "example.com" || p != 443 || ttl != 5*time.Second {
+		t.Errorf("actual = %v, %v, %v", host, p, ttl)
+	}
+
+	if err := SplitIntoAny("80:a:b", ":", &p, &rest); err != nil {
+		t.Fatalf("err = %s", err)
+	}
+	if p != 80 || rest != "a:b" {
+		t.Errorf("actual = %v, %v", p, rest)
+	}
+}
+
+type endpoint struct {
+	Host    string        `stringz:"0"`
+	Port    port          `stringz:"1"`
+	Timeout time.Duration `stringz:"2,optional"`
+	Path    string        `stringz:"rest"`
+	Ignored string
+}
+
+func TestSplitStruct(t *testing.T) {
+	table := []struct {
+		description string
+		s           string
+		expected    endpoint
+	}{
+		{"required only", "example.com:80", endpoint{Host: "example.com", Port: 80, Timeout: time.Minute}},
+		{"optional", "example.com:80:5s", endpoint{Host: "example.com", Port: 80, Timeout: 5 * time.Second}},
+		{"rest", "example.com:80:5s:a:b", endpoint{Host: "example.com", Port: 80, Timeout: 5 * time.Second, Path: "a:b"}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := endpoint{Timeout: time.Minute, Path: "stale"}
+			if err := SplitStruct(tt.s, ":", &actual); err != nil {
+				t.Fatalf("err = %s", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("actual = %+v; want = %+v", actual, tt.expected)
+			}
+		})
+	}
+
+	var list struct {
+		Name  string   `stringz:"0"`
+		Flags []string `stringz:"rest"`
+	}
+	if err := SplitStruct("x,a,b", ",", &list); err != nil || list.Name != "x" || len(list.Flags) != 2 {
+		t.Errorf("actual = %+v, %v", list, err)
+	}
+}
+
+func TestSplitStructErrors(t *testing.T) {
+	var strict struct {
+		Host string `stringz:"0"`
+		Port port   `stringz:"1"`
+	}
+	table := []struct {
+		description string
+		s           string
+		field       string
+		index       int
+		expectedErr error
+	}{
+		{"missing segment", "example.com", "Port", 1, ErrInconsistentUnpackLen},
+		{"invalid segment", "example.com:http", "Port", 1, strconv.ErrSyntax},
+		{"overflow", "example.com:70000", "Port", 1, strconv.ErrRange},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.description, func(t *testing.T) {
+			err := SplitStruct(tt.s, ":", &strict)
+			var unpackErr *UnpackError
+			if !errors.As(err, &unpackErr) {
+				t.Fatalf("err = %v; want = *UnpackError", err)
+			}
+			if unpackErr.Field != tt.field || unpackErr.Index != tt.index {
+				t.Errorf("Field, Index = %s, %d; want = %s, %d", unpackErr.Field, unpackErr.Index, tt.field, tt.index)
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.expectedErr)
+			}
+		})
+	}
+
+	if err := SplitStruct("a:1:extra", ":", &strict); !errors.Is(err, ErrInconsistentUnpackLen) {
+		t.Errorf("err = %v; want = %v", err, ErrInconsistentUnpackLen)
+	}
+
+	var duplicate struct {
+		A string `stringz:"0"`
+		B string `stringz:"0"`
+	}
+	var badTag struct {
+		A string `stringz:"first"`
+	}
+	var badRest struct {
+		A int `stringz:"rest"`
+	}
+	var gap struct {
+		A string `stringz:"0"`
+		C string `stringz:"2"`
+	}
+	for _, dst := range []any{&duplicate, &badTag, &badRest, &gap, strict, nil} {
+		if err := SplitStruct("a", ":", dst); err == nil {
+			t.Errorf("SplitStruct(%T) err = nil", dst)
+		}
+	}
+}
+
+func TestUnpackAnyNamedDuration(t *testing.T) {
+	var d timeout
+	if err := UnpackAny([]string{"5000"}, &d); err != nil || d != 5000 {
+		t.Errorf("actual = %v, %v; want = 5000, nil", d, err)
+	}
+
+	err := UnpackAny([]string{"5s"}, &d)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("err = %v; want = %v", err, strconv.ErrSyntax)
+	}
+}
+
+func TestSplitStructGap(t *testing.T) {
+	var gap struct {
+		A string `stringz:"0"`
+		C string `stringz:"2"`
+	}
+	err := SplitStruct("a,b,c", ",", &gap)
+	if err == nil || !strings.Contains(err.Error(), "segment 1") {
+		t.Errorf("err = %v; want an error naming segment 1", err)
+	}
+	if gap.A != "" || gap.C != "" {
+		t.Errorf("actual = %+v; want no fields assigned", gap)
+	}
+}